@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"math"
@@ -189,18 +190,29 @@ func (f Form) Windows() (windows []Window) {
 	return
 }
 
-// getBox 查找当前及子结构中所有在图层中的实体组件(递归)
-func getBox(doc *dxf.Document, layer string, entity entities.Entity, parent *entities.Insert) (boxes []core.BBox) {
+// getBox 查找当前及子结构中所有在图层中的实体组件(递归)，parent 为块坐标到世界坐标的变换矩阵
+// inherit 为块中 "0" 层实体继承的图层，有效图层被隐藏 (Document.Hidden) 的实体不收集
+func getBox(doc *dxf.Document, layer string, entity entities.Entity, parent core.Matrix, inherit string) (boxes []core.BBox) {
 	if entity == nil {
 		return
 	}
 
-	// 收集 PJ 层线条
-	if entity.Layer() == layer {
-		if parent == nil {
-			boxes = append(boxes, entity.BBox())
+	effective := entity.Layer()
+	if effective == "" || effective == "0" {
+		effective = inherit
+	}
+	hidden := doc != nil && doc.Hidden(effective)
+
+	// 收集 PJ 层线条，可变换的实体先变换到世界坐标再计算包围盒，旋转后的包围盒不会被放大
+	if entity.Layer() == layer && !hidden && !entities.IsEmpty(entity) {
+		var box core.BBox
+		if t, ok := entity.(entities.Transformer); ok {
+			box = t.Transformed(parent).BBox()
 		} else {
-			boxes = append(boxes, utils.TransformBBox(entity.BBox(), parent))
+			box = parent.ApplyBBox(entity.BBox())
+		}
+		if box.Bounded() {
+			boxes = append(boxes, box)
 		}
 	}
 
@@ -209,18 +221,18 @@ func getBox(doc *dxf.Document, layer string, entity entities.Entity, parent *ent
 		return
 	}
 
-	block, exists := doc.Blocks[insert.BlockName]
-	if !exists {
+	block := doc.Block(insert.BlockName)
+	if block == nil {
 		return
 	}
 
-	if parent != nil {
-		insert = utils.CombineInserts(parent, insert)
-	}
-
-	for _, sub := range block.Entities {
-		for _, box := range getBox(doc, layer, sub, insert) {
-			boxes = append(boxes, box)
+	// 阵列块参照的每个单元分别收集
+	for cell := range insert.Transforms(block.BasePoint) {
+		transform := parent.Multiply(cell)
+		for _, sub := range block.Entities {
+			for _, box := range getBox(doc, layer, sub, transform, effective) {
+				boxes = append(boxes, box)
+			}
 		}
 	}
 
@@ -365,35 +377,52 @@ func selectFile() (filename string, err error) {
 	)
 }
 
-// 进度条处理
-func handleProgress(title string, fn func(dialog zenity.ProgressDialog)) {
+// 进度条处理，用户取消或关闭进度条时取消 ctx，等待回调函数返回后退出程序
+func handleProgress(title string, fn func(ctx context.Context, dialog zenity.ProgressDialog)) {
 	dialog, err := zenity.Progress(
 		guiTitle(title),
 		zenity.EntryText("正在提取 DXF 图层数据，请稍候..."),
 		zenity.Pulsate(),
 		zenity.MaxValue(100),
-		zenity.Modal(),    // 开启模态会阻塞，必须提前执行回调函数
-		zenity.NoCancel(), // 如果你不希望用户中途关闭，可以加这一行
+		zenity.Modal(), // 开启模态会阻塞，必须提前执行回调函数
 	)
 	if err != nil {
 		showMessage(zenity.Error, err.Error(), guiTitle("打开进度条错误"))
 		os.Exit(4)
 	}
 
-	go func() {
-		defer func() { _ = dialog.Close() }()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-		fn(dialog)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
 
-		_ = dialog.Complete()
+		fn(ctx, dialog)
 	}()
 
-	<-dialog.Done()
+	select {
+	case <-done:
+		_ = dialog.Complete()
+		_ = dialog.Close()
+	case <-dialog.Done():
+		cancel()
+		<-done
+		showMessage(zenity.Warning, "取消"+title+"，即将退出程序！", guiTitle(title+"提示"))
+		os.Exit(1)
+	}
 }
 
-// 打开文件，报错则退出
-func openFile(filename string) *dxf.Document {
-	doc, err := dxf.Open(filename)
+// 打开文件并显示读取进度，报错则退出，ctx 取消时停止读取并返回 nil
+func openFile(ctx context.Context, dialog zenity.ProgressDialog, filename string) *dxf.Document {
+	doc, err := dxf.OpenContext(ctx, filename, dxf.WithProgress(func(p dxf.Progress) {
+		if p.Total > 0 {
+			setPercent(dialog, "读取图纸 "+p.Section, int(p.Read), int(p.Total))
+		}
+	}))
+	if ctx.Err() != nil {
+		return nil
+	}
 	if err != nil {
 		showMessage(zenity.Error, err.Error(), guiTitle("打开文件错误"))
 		os.Exit(2)
@@ -512,7 +541,7 @@ func getForms(dialog zenity.ProgressDialog, doc *dxf.Document) []Form {
 			bzs = append(bzs, e)
 		}
 
-		pjs = append(pjs, getBox(doc, "PJ", entity, nil)...)
+		pjs = append(pjs, getBox(doc, "PJ", entity, core.Identity(), "0")...)
 	}
 
 	// 2. 排序确认单A4 TKA4 (按 X 坐标，从左到右，符合人类阅读)
@@ -722,12 +751,17 @@ func RevealFile(filename string) error {
 func main() {
 	var (
 		forms    []Form
-		input    = getInput()      // 获取输入文件
-		document = openFile(input) // 打开输入文件
+		document *dxf.Document
+		input    = getInput() // 获取输入文件
 	)
 
+	// 打开输入文件
+	handleProgress("打开文件", func(ctx context.Context, dialog zenity.ProgressDialog) {
+		document = openFile(ctx, dialog, input)
+	})
+
 	// 提取数据
-	handleProgress("提取数据", func(dialog zenity.ProgressDialog) {
+	handleProgress("提取数据", func(_ context.Context, dialog zenity.ProgressDialog) {
 		forms = getForms(dialog, document)
 	})
 
@@ -735,7 +769,7 @@ func main() {
 	var output = getOutput(input)
 
 	// 保存文件
-	handleProgress("保存文件", func(dialog zenity.ProgressDialog) {
+	handleProgress("保存文件", func(_ context.Context, dialog zenity.ProgressDialog) {
 		saveFile(dialog, input, output, forms)
 	})
 
@@ -0,0 +1,191 @@
+package core
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// BinarySentinel 二进制 DXF 文件的起始标识 (22 字节)
+const BinarySentinel = "AutoCAD Binary DXF\r\n\x1a\x00"
+
+// 二进制 DXF 中组码对应的值类型
+const (
+	binaryString = iota
+	binaryInt16
+	binaryInt32
+	binaryInt64
+	binaryDouble
+	binaryBool
+	binaryChunk
+)
+
+// binaryType 根据组码范围判定二进制值的存储类型
+func binaryType(code int) int {
+	switch {
+	case code >= 10 && code <= 59,
+		code >= 110 && code <= 149,
+		code >= 210 && code <= 239,
+		code >= 460 && code <= 469,
+		code >= 1010 && code <= 1059:
+		return binaryDouble
+	case code >= 60 && code <= 79,
+		code >= 170 && code <= 179,
+		code >= 270 && code <= 289,
+		code >= 370 && code <= 389,
+		code >= 400 && code <= 409,
+		code >= 1060 && code <= 1070:
+		return binaryInt16
+	case code >= 90 && code <= 99,
+		code >= 420 && code <= 429,
+		code >= 440 && code <= 449,
+		code == 1071:
+		return binaryInt32
+	case code >= 160 && code <= 169,
+		code >= 450 && code <= 459:
+		return binaryInt64
+	case code >= 290 && code <= 299:
+		return binaryBool
+	case code >= 310 && code <= 319,
+		code == 1004:
+		return binaryChunk
+	default:
+		return binaryString
+	}
+}
+
+// detect 检查是否为二进制 DXF，并判定组码宽度
+// R13 及以后的组码占 2 字节，R12 及以前占 1 字节 (255 为扩展前缀)
+func (s *Scanner) detect() {
+	s.detected = true
+
+	head, err := s.reader.Peek(len(BinarySentinel))
+	if err != nil || string(head) != BinarySentinel {
+		return
+	}
+
+	_, _ = s.reader.Discard(len(BinarySentinel))
+	s.binary = true
+	s.codeSize = 2
+
+	// 第一个标签必然是 (0, "SECTION")：R12 第 2 个字节是 'S'，R13+ 是 0
+	if first, err := s.reader.Peek(2); err == nil && first[1] != 0 {
+		s.codeSize = 1
+	}
+}
+
+// readCode 读取二进制组码
+func (s *Scanner) readCode() (int, error) {
+	if s.codeSize == 2 {
+		var buf [2]byte
+		if _, err := io.ReadFull(s.reader, buf[:]); err != nil {
+			return 0, err
+		}
+		return int(int16(binary.LittleEndian.Uint16(buf[:]))), nil
+	}
+
+	b, err := s.reader.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if b != 255 {
+		return int(b), nil
+	}
+
+	var buf [2]byte
+	if _, err = io.ReadFull(s.reader, buf[:]); err != nil {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return int(int16(binary.LittleEndian.Uint16(buf[:]))), nil
+}
+
+// readValue 按组码类型读取二进制值，并转换为与 ASCII DXF 一致的文本形式
+func (s *Scanner) readValue(code int) (string, error) {
+	switch binaryType(code) {
+	case binaryInt16:
+		var buf [2]byte
+		if _, err := io.ReadFull(s.reader, buf[:]); err != nil {
+			return "", err
+		}
+		return strconv.Itoa(int(int16(binary.LittleEndian.Uint16(buf[:])))), nil
+	case binaryInt32:
+		var buf [4]byte
+		if _, err := io.ReadFull(s.reader, buf[:]); err != nil {
+			return "", err
+		}
+		return strconv.Itoa(int(int32(binary.LittleEndian.Uint32(buf[:])))), nil
+	case binaryInt64:
+		var buf [8]byte
+		if _, err := io.ReadFull(s.reader, buf[:]); err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(int64(binary.LittleEndian.Uint64(buf[:])), 10), nil
+	case binaryDouble:
+		var buf [8]byte
+		if _, err := io.ReadFull(s.reader, buf[:]); err != nil {
+			return "", err
+		}
+		f := math.Float64frombits(binary.LittleEndian.Uint64(buf[:]))
+		return strconv.FormatFloat(f, 'f', -1, 64), nil
+	case binaryBool:
+		b, err := s.reader.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		if b != 0 {
+			return "1", nil
+		}
+		return "0", nil
+	case binaryChunk:
+		// 二进制块：1 字节长度 + 数据，转为 ASCII DXF 中的十六进制表示
+		n, err := s.reader.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		buf := make([]byte, n)
+		if _, err = io.ReadFull(s.reader, buf); err != nil {
+			return "", err
+		}
+		return strings.ToUpper(hex.EncodeToString(buf)), nil
+	default:
+		// 字符串以 0 结尾
+		str, err := s.reader.ReadString(0)
+		if err != nil {
+			return "", err
+		}
+		return str[:len(str)-1], nil
+	}
+}
+
+// nextBinary 读取下一个二进制标签
+func (s *Scanner) nextBinary() bool {
+	code, err := s.readCode()
+	if err != nil {
+		if err != io.EOF {
+			s.err = s.errorf(-1, "", err)
+		}
+		return false
+	}
+
+	value, err := s.readValue(code)
+	if err != nil {
+		// 组码后缺少值，文件不完整
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		s.err = s.errorf(code, "", err)
+		return false
+	}
+
+	s.LastTag = Tag{Code: code, Value: s.decode(value)}
+	return true
+}
+
+// IsBinary 返回当前扫描的是否为二进制 DXF
+func (s *Scanner) IsBinary() bool {
+	return s.binary
+}
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// codePages $DWGCODEPAGE 与字符编码的对应关系
+var codePages = map[string]encoding.Encoding{
+	"ANSI_874":  charmap.Windows874,
+	"ANSI_932":  japanese.ShiftJIS,
+	"ANSI_936":  simplifiedchinese.GBK,
+	"ANSI_949":  korean.EUCKR,
+	"ANSI_950":  traditionalchinese.Big5,
+	"ANSI_1250": charmap.Windows1250,
+	"ANSI_1251": charmap.Windows1251,
+	"ANSI_1252": charmap.Windows1252,
+	"ANSI_1253": charmap.Windows1253,
+	"ANSI_1254": charmap.Windows1254,
+	"ANSI_1255": charmap.Windows1255,
+	"ANSI_1256": charmap.Windows1256,
+	"ANSI_1257": charmap.Windows1257,
+	"ANSI_1258": charmap.Windows1258,
+	"DOS437":    charmap.CodePage437,
+	"DOS850":    charmap.CodePage850,
+	"DOS866":    charmap.CodePage866,
+	"GB2312":    simplifiedchinese.GBK,
+	"BIG5":      traditionalchinese.Big5,
+	"GBK":       simplifiedchinese.GBK,
+	"SHIFT_JIS": japanese.ShiftJIS,
+}
+
+// mifPages \M+nXXXX 转义中 n 对应的字符编码
+var mifPages = map[byte]encoding.Encoding{
+	'1': japanese.ShiftJIS,       // ANSI_932
+	'2': traditionalchinese.Big5, // ANSI_950
+	'3': korean.EUCKR,            // ANSI_949
+	'5': simplifiedchinese.GBK,   // ANSI_936
+}
+
+// CodePageEncoding 根据 $DWGCODEPAGE 返回字符编码，未知或 UTF-8 返回 nil
+func CodePageEncoding(codePage string) encoding.Encoding {
+	return codePages[strings.ToUpper(strings.TrimSpace(codePage))]
+}
+
+// SetEncoding 设置字符串值的编码，之后读取的值都会转为 UTF-8，nil 表示不转换
+func (s *Scanner) SetEncoding(enc encoding.Encoding) {
+	s.decoder = nil
+	if enc != nil {
+		s.decoder = enc.NewDecoder()
+	}
+}
+
+// decode 将原始值转为 UTF-8 并展开 unicode 转义
+func (s *Scanner) decode(value string) string {
+	if s.decoder != nil && !isASCII(value) {
+		if str, err := s.decoder.String(value); err == nil {
+			value = str
+		}
+	}
+
+	return DecodeUnicode(value)
+}
+
+// DecodeUnicode 展开 DXF 字符串中的 \U+XXXX 与 \M+nXXXX 转义
+func DecodeUnicode(s string) string {
+	if !strings.Contains(s, `\U+`) && !strings.Contains(s, `\M+`) &&
+		!strings.Contains(s, `\u+`) && !strings.Contains(s, `\m+`) {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); {
+		if s[i] == '\\' && i+2 < len(s) && s[i+2] == '+' {
+			switch s[i+1] {
+			case 'U', 'u':
+				if r, ok := parseHex(s[i+3:], 4); ok {
+					i += 7
+					// UTF-16 代理对：\U+D83D\U+DE00
+					if utf16.IsSurrogate(r) {
+						if low, ok := parseUnicodeAt(s, i); ok {
+							if pair := utf16.DecodeRune(r, low); pair != utf8.RuneError {
+								r = pair
+								i += 7
+							}
+						}
+					}
+					b.WriteRune(r)
+					continue
+				}
+			case 'M', 'm':
+				if i+4 < len(s) {
+					if enc, ok := mifPages[s[i+3]]; ok {
+						if code, ok := parseHex(s[i+4:], 4); ok {
+							raw := string([]byte{byte(code >> 8), byte(code)})
+							if str, err := enc.NewDecoder().String(raw); err == nil {
+								b.WriteString(str)
+								i += 8
+								continue
+							}
+						}
+					}
+				}
+			}
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+
+	return b.String()
+}
+
+// parseUnicodeAt 解析 s[i:] 处的 \U+XXXX
+func parseUnicodeAt(s string, i int) (rune, bool) {
+	if i+7 > len(s) || s[i] != '\\' || (s[i+1] != 'U' && s[i+1] != 'u') || s[i+2] != '+' {
+		return 0, false
+	}
+	return parseHex(s[i+3:], 4)
+}
+
+// parseHex 解析 s 开头 n 位十六进制数
+func parseHex(s string, n int) (rune, bool) {
+	if len(s) < n {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(s[:n], 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return rune(v), true
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeUnicode 将非 ASCII 字符转为 \U+XXXX 转义，供 R2000 及以前的 DXF 使用
+func EncodeUnicode(s string) string {
+	if isASCII(s) {
+		return s
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r < utf8.RuneSelf:
+			b.WriteRune(r)
+		case r > 0xFFFF:
+			r1, r2 := utf16.EncodeRune(r)
+			fmt.Fprintf(&b, `\U+%04X\U+%04X`, r1, r2)
+		default:
+			fmt.Fprintf(&b, `\U+%04X`, r)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+func TestDecodeUnicode(t *testing.T) {
+	cases := map[string]string{
+		`C1518`:              "C1518",
+		`\U+697C\U+53F7`:     "楼号",
+		`%%c\U+00B0A`:        "%%c°A",
+		`\M+5C2A5A`:          "楼A",
+		`\U+D83D\U+DE00`:     "😀",
+		`\U+ZZZZ`:            `\U+ZZZZ`,
+		`\P\U+5E8F\U+53F7\P`: `\P序号\P`,
+	}
+
+	for in, want := range cases {
+		if got := DecodeUnicode(in); got != want {
+			t.Errorf("DecodeUnicode(%q): 期望 %q, 得到 %q", in, want, got)
+		}
+	}
+}
+
+func TestScanner_Encoding(t *testing.T) {
+	gbk, _ := simplifiedchinese.GBK.NewEncoder().String("面积")
+	scanner := NewScanner(strings.NewReader("1\n" + gbk + "\n"))
+	scanner.SetEncoding(CodePageEncoding("ansi_936"))
+
+	if !scanner.Next() {
+		t.Fatal(scanner.Err())
+	}
+	if scanner.LastTag.Value != "面积" {
+		t.Errorf("GBK 解码失败: 得到 %q", scanner.LastTag.Value)
+	}
+}
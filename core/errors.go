@@ -0,0 +1,48 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError 带位置信息的解析错误
+type ParseError struct {
+	Line   int    // 组码所在行号，从 1 开始 (二进制 DXF 为 0)
+	Offset int64  // 组码在文件中的字节偏移
+	Code   int    // 组码，组码本身无法解析时为 -1
+	Value  string // 原始值
+	Entity string // 所在实体 (或表记录、段) 的类型
+	Handle string // 所在实体的句柄
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	var b strings.Builder
+
+	b.WriteString("dxf: ")
+	if e.Line > 0 {
+		fmt.Fprintf(&b, "line %d: ", e.Line)
+	} else {
+		fmt.Fprintf(&b, "offset %d: ", e.Offset)
+	}
+	if e.Entity != "" {
+		b.WriteString(e.Entity)
+		if e.Handle != "" {
+			fmt.Fprintf(&b, "(%s)", e.Handle)
+		}
+		b.WriteString(": ")
+	}
+	if e.Code >= 0 {
+		fmt.Fprintf(&b, "group code %d ", e.Code)
+	}
+	fmt.Fprintf(&b, "value %q", e.Value)
+	if e.Err != nil {
+		fmt.Fprintf(&b, ": %v", e.Err)
+	}
+
+	return b.String()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
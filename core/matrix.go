@@ -0,0 +1,151 @@
+package core
+
+import "math"
+
+// Matrix 4x4 仿射变换矩阵，按列向量约定：p' = M·p
+// 组合变换时 A.Multiply(B) 表示先应用 B 再应用 A
+type Matrix [4][4]float64
+
+// Identity 返回单位矩阵
+func Identity() Matrix {
+	return Matrix{
+		{1, 0, 0, 0},
+		{0, 1, 0, 0},
+		{0, 0, 1, 0},
+		{0, 0, 0, 1},
+	}
+}
+
+// Translation 返回平移矩阵
+func Translation(offset Point) Matrix {
+	m := Identity()
+	m[0][3], m[1][3], m[2][3] = offset.X, offset.Y, offset.Z
+	return m
+}
+
+// Scaling 返回缩放矩阵，负数表示镜像
+func Scaling(scale Point) Matrix {
+	m := Identity()
+	m[0][0], m[1][1], m[2][2] = scale.X, scale.Y, scale.Z
+	return m
+}
+
+// RotationZ 返回绕 Z 轴逆时针旋转的矩阵，angle 单位为度
+func RotationZ(angle float64) Matrix {
+	rad := angle * math.Pi / 180.0
+	cos, sin := math.Cos(rad), math.Sin(rad)
+
+	m := Identity()
+	m[0][0], m[0][1] = cos, -sin
+	m[1][0], m[1][1] = sin, cos
+	return m
+}
+
+// Multiply 返回 m·n，即先应用 n 再应用 m
+func (m Matrix) Multiply(n Matrix) Matrix {
+	var r Matrix
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			for k := 0; k < 4; k++ {
+				r[i][j] += m[i][k] * n[k][j]
+			}
+		}
+	}
+	return r
+}
+
+// Determinant 返回线性部分 (左上 3x3) 的行列式，小于 0 表示变换包含镜像
+func (m Matrix) Determinant() float64 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// Inverse 返回逆矩阵，矩阵不可逆 (如缩放为 0) 时 ok 为 false
+func (m Matrix) Inverse() (inv Matrix, ok bool) {
+	det := m.Determinant()
+	if math.Abs(det) < 1e-12 {
+		return Matrix{}, false
+	}
+
+	// 线性部分求逆 (伴随矩阵 / 行列式)
+	inv[0][0] = (m[1][1]*m[2][2] - m[1][2]*m[2][1]) / det
+	inv[0][1] = (m[0][2]*m[2][1] - m[0][1]*m[2][2]) / det
+	inv[0][2] = (m[0][1]*m[1][2] - m[0][2]*m[1][1]) / det
+	inv[1][0] = (m[1][2]*m[2][0] - m[1][0]*m[2][2]) / det
+	inv[1][1] = (m[0][0]*m[2][2] - m[0][2]*m[2][0]) / det
+	inv[1][2] = (m[0][2]*m[1][0] - m[0][0]*m[1][2]) / det
+	inv[2][0] = (m[1][0]*m[2][1] - m[1][1]*m[2][0]) / det
+	inv[2][1] = (m[0][1]*m[2][0] - m[0][0]*m[2][1]) / det
+	inv[2][2] = (m[0][0]*m[1][1] - m[0][1]*m[1][0]) / det
+
+	// 平移部分：-inv·t
+	for i := 0; i < 3; i++ {
+		inv[i][3] = -(inv[i][0]*m[0][3] + inv[i][1]*m[1][3] + inv[i][2]*m[2][3])
+	}
+	inv[3][3] = 1
+
+	return inv, true
+}
+
+// Apply 变换点 (含平移)
+func (m Matrix) Apply(p Point) Point {
+	return Point{
+		X: m[0][0]*p.X + m[0][1]*p.Y + m[0][2]*p.Z + m[0][3],
+		Y: m[1][0]*p.X + m[1][1]*p.Y + m[1][2]*p.Z + m[1][3],
+		Z: m[2][0]*p.X + m[2][1]*p.Y + m[2][2]*p.Z + m[2][3],
+	}
+}
+
+// ApplyVector 变换向量 (不含平移)，如方向、长轴
+func (m Matrix) ApplyVector(v Point) Point {
+	return Point{
+		X: m[0][0]*v.X + m[0][1]*v.Y + m[0][2]*v.Z,
+		Y: m[1][0]*v.X + m[1][1]*v.Y + m[1][2]*v.Z,
+		Z: m[2][0]*v.X + m[2][1]*v.Y + m[2][2]*v.Z,
+	}
+}
+
+// AxisAligned 判断线性部分是否把坐标轴映射到坐标轴 (缩放、镜像与 90° 整数倍的旋转)
+// 此时 ApplyBBox 得到的就是变换后图形的精确包围盒
+func (m Matrix) AxisAligned() bool {
+	for i := 0; i < 3; i++ {
+		row := m[i][:3]
+		limit := 1e-12 * math.Max(math.Abs(row[0]), math.Max(math.Abs(row[1]), math.Abs(row[2])))
+		n := 0
+		for _, v := range row {
+			if math.Abs(v) > limit {
+				n++
+			}
+		}
+		if n > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyBBox 变换包围盒的 8 个角点，返回变换后的轴对齐包围盒
+func (m Matrix) ApplyBBox(b BBox) BBox {
+	var (
+		xs = [2]float64{b.Min.X, b.Max.X}
+		ys = [2]float64{b.Min.Y, b.Max.Y}
+		zs = [2]float64{b.Min.Z, b.Max.Z}
+		r  = BBox{
+			Min: Point{X: math.MaxFloat64, Y: math.MaxFloat64, Z: math.MaxFloat64},
+			Max: Point{X: -math.MaxFloat64, Y: -math.MaxFloat64, Z: -math.MaxFloat64},
+		}
+	)
+
+	for _, x := range xs {
+		for _, y := range ys {
+			for _, z := range zs {
+				p := m.Apply(Point{X: x, Y: y, Z: z})
+				r.Min.X, r.Min.Y, r.Min.Z = math.Min(r.Min.X, p.X), math.Min(r.Min.Y, p.Y), math.Min(r.Min.Z, p.Z)
+				r.Max.X, r.Max.Y, r.Max.Z = math.Max(r.Max.X, p.X), math.Max(r.Max.Y, p.Y), math.Max(r.Max.Z, p.Z)
+			}
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMatrix(t *testing.T) {
+	near := func(a, b Point) bool {
+		return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9 && math.Abs(a.Z-b.Z) < 1e-9
+	}
+
+	// 父级：X 方向镜像并放大 2 倍；子级：旋转 90° 后平移 (1,0)
+	parent := Translation(Point{X: 10}).Multiply(Scaling(Point{X: -2, Y: 1, Z: 1}))
+	child := Translation(Point{X: 1}).Multiply(RotationZ(90))
+	m := parent.Multiply(child)
+
+	// (1,0) → 子级 (1,1) → 父级 (8,1)
+	if p := m.Apply(Point{X: 1}); !near(p, Point{X: 8, Y: 1}) {
+		t.Errorf("Apply 不正确: %+v", p)
+	}
+	if v := m.ApplyVector(Point{X: 1}); !near(v, Point{Y: 1}) {
+		t.Errorf("ApplyVector 不正确: %+v", v)
+	}
+	if m.Determinant() >= 0 {
+		t.Error("镜像变换的行列式应小于 0")
+	}
+
+	inv, ok := m.Inverse()
+	if !ok {
+		t.Fatal("矩阵应可逆")
+	}
+	if p := inv.Apply(Point{X: 8, Y: 1, Z: 3}); !near(p, Point{X: 1, Z: 3}) {
+		t.Errorf("Inverse 不正确: %+v", p)
+	}
+	if _, ok = Scaling(Point{X: 1}).Inverse(); ok {
+		t.Error("缩放为 0 的矩阵不可逆")
+	}
+
+	box := RotationZ(45).ApplyBBox(BBox{Max: Point{X: 1, Y: 1}})
+	if !near(box.Min, Point{X: -math.Sqrt2 / 2}) || !near(box.Max, Point{X: math.Sqrt2 / 2, Y: math.Sqrt2}) {
+		t.Errorf("ApplyBBox 不正确: %+v", box)
+	}
+}
@@ -2,59 +2,300 @@ package core
 
 import (
 	"bufio"
+	"errors"
 	"io"
 	"strconv"
 	"strings"
+
+	"golang.org/x/text/encoding"
 )
 
 type Scanner struct {
-	reader  *bufio.Reader
-	LastTag Tag
-	err     error
+	reader   *bufio.Reader
+	counter  *countReader
+	LastTag  Tag
+	err      error
+	detected bool  // 是否已检测文件格式
+	binary   bool  // 是否为二进制 DXF
+	codeSize int   // 二进制组码字节数
+	done     bool  // 是否已读完 (EOF 或出错)
+	pending  []Tag // 预读或被 Unread 退回的标签，Next 优先从这里返回
+
+	decoder *encoding.Decoder // 字符串值解码器 (代码页 → UTF-8)
+
+	line      int    // 已读取的行数
+	tagLine   int    // LastTag 组码所在行
+	tagOffset int64  // LastTag 组码的字节偏移
+	entity    string // LastTag 所在实体的类型
+	handle    string // LastTag 所在实体的句柄
+
+	strict   bool          // 严格模式：数值无法解析时立即报错
+	warnings []*ParseError // 宽松模式下收集的数值解析错误
+}
+
+// countReader 统计从底层读取的字节数
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
 }
 
 func NewScanner(r io.Reader) *Scanner {
+	counter := &countReader{r: r}
 	return &Scanner{
-		reader: bufio.NewReader(r),
+		reader:  bufio.NewReader(counter),
+		counter: counter,
 	}
 }
 
+// Next 读取下一组标签到 LastTag，读完或出错时返回 false
 func (s *Scanner) Next() bool {
+	if len(s.pending) > 0 {
+		s.LastTag, s.pending = s.pending[0], s.pending[1:]
+		return true
+	}
+	return s.read()
+}
+
+// read 从数据流中读取下一组标签到 LastTag
+func (s *Scanner) read() bool {
+	if s.done {
+		return false
+	}
+	if !s.next() {
+		s.done = true
+		return false
+	}
+
+	s.track()
+	if !s.binary {
+		if err := s.validate(); err != nil {
+			if s.strict {
+				s.err = err
+				s.done = true
+				return false
+			}
+			s.warnings = append(s.warnings, err)
+		}
+	}
+
+	return true
+}
+
+func (s *Scanner) next() bool {
+	if !s.detected {
+		s.detect()
+	}
+
+	s.tagOffset = s.Offset()
+
+	if s.binary {
+		return s.nextBinary()
+	}
+
 	// 1. 读取 Code 行
 	codeLine, err := s.reader.ReadString('\n')
-	if err != nil {
+	if codeLine != "" {
+		s.line++
+	}
+	if err != nil && (err != io.EOF || strings.TrimSpace(codeLine) == "") {
 		if err != io.EOF {
-			s.err = err
+			s.err = s.errorf(-1, codeLine, err)
 		}
 		return false
 	}
 
 	codeStr := strings.TrimSpace(codeLine)
 	if codeStr == "" { // 跳过空行
-		return s.Next()
+		return s.next()
 	}
 
+	s.tagLine = s.line
 	code, err := strconv.Atoi(codeStr)
 	if err != nil {
-		s.err = err
+		s.err = s.errorf(-1, codeStr, errors.New("invalid group code"))
 		return false
 	}
 
 	// 2. 读取 Value 行
 	valueLine, err := s.reader.ReadString('\n')
-	if err != nil {
-		// Value 行如果 EOF 也是不完整的
-		s.err = err
+	if valueLine != "" {
+		s.line++
+	}
+	// 最后一行没有换行符时仍然有效，但缺少 Value 行说明文件不完整
+	if err != nil && (err != io.EOF || valueLine == "") {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		s.err = s.errorf(code, "", err)
 		return false
 	}
 
 	// 去掉行尾的换行符，但保留 Value 开头的空格（DXF 规范要求）
 	value := strings.TrimRight(valueLine, "\r\n")
 
-	s.LastTag = Tag{Code: code, Value: value}
+	s.LastTag = Tag{Code: code, Value: s.decode(value)}
+	return true
+}
+
+// Unread 退回 LastTag，下一次 Next 会再次返回它
+func (s *Scanner) Unread() {
+	if len(s.pending) == 0 {
+		s.pending = append(s.pending[:0], s.LastTag)
+		return
+	}
+	s.pending = append([]Tag{s.LastTag}, s.pending...)
+}
+
+// Peek 返回下一组标签但不消费它，LastTag 保持不变
+func (s *Scanner) Peek() (Tag, bool) {
+	if len(s.pending) == 0 && !s.prefetch() {
+		return Tag{}, false
+	}
+	return s.pending[0], true
+}
+
+// PeekEntity 预读 LastTag 之后直到下一个组码 0 之前的所有标签，但不消费它们
+// 用于在解析实体之前查看其图层等属性，返回的切片在下一次 Next 之前有效
+func (s *Scanner) PeekEntity() []Tag {
+	for i := 0; ; i++ {
+		if i == len(s.pending) && !s.prefetch() {
+			return s.pending
+		}
+		if s.pending[i].Code == 0 {
+			return s.pending[:i]
+		}
+	}
+}
+
+// prefetch 从数据流中多读一组标签放入 pending，LastTag 保持不变
+func (s *Scanner) prefetch() bool {
+	last := s.LastTag
+	defer func() { s.LastTag = last }()
+
+	if !s.read() {
+		return false
+	}
+	s.pending = append(s.pending, s.LastTag)
 	return true
 }
 
+// ReadEntity 读取 LastTag 之后直到下一个组码 0 之前的所有标签
+// 下一个组码 0 不会被消费，随后的 Next 会返回它
+func (s *Scanner) ReadEntity() []Tag {
+	var tags []Tag
+	for s.Next() {
+		if s.LastTag.Code == 0 {
+			s.Unread()
+			break
+		}
+		tags = append(tags, s.LastTag)
+	}
+	return tags
+}
+
+// Skip 跳过 LastTag 之后直到下一个组码 0 之前的所有标签，不保存它们
+func (s *Scanner) Skip() {
+	for s.Next() {
+		if s.LastTag.Code == 0 {
+			s.Unread()
+			break
+		}
+	}
+}
+
+// track 记录当前所在的实体，用于错误定位
+func (s *Scanner) track() {
+	switch s.LastTag.Code {
+	case 0:
+		s.entity, s.handle = s.LastTag.AsString(), ""
+	case 2:
+		// 段名、表名
+		if s.entity == "SECTION" || s.entity == "TABLE" {
+			s.entity = s.LastTag.AsString()
+		}
+	case 9:
+		// HEADER 变量名
+		s.entity = s.LastTag.AsString()
+	case 5, 105:
+		if s.handle == "" {
+			s.handle = s.LastTag.AsString()
+		}
+	}
+}
+
+// validate 按组码类型校验数值 (ASCII DXF)
+func (s *Scanner) validate() *ParseError {
+	var (
+		tag   = s.LastTag
+		value = strings.TrimSpace(tag.Value)
+		err   error
+	)
+
+	switch binaryType(tag.Code) {
+	case binaryDouble:
+		_, err = strconv.ParseFloat(value, 64)
+	case binaryInt16, binaryInt32, binaryInt64, binaryBool:
+		_, err = strconv.ParseInt(value, 10, 64)
+	default:
+		return nil
+	}
+
+	if err != nil {
+		var numErr *strconv.NumError
+		if errors.As(err, &numErr) {
+			err = numErr.Err
+		}
+		return s.errorf(tag.Code, tag.Value, err)
+	}
+
+	return nil
+}
+
+// errorf 以当前位置创建解析错误
+func (s *Scanner) errorf(code int, value string, err error) *ParseError {
+	return &ParseError{
+		Line:   s.tagLine,
+		Offset: s.tagOffset,
+		Code:   code,
+		Value:  value,
+		Entity: s.entity,
+		Handle: s.handle,
+		Err:    err,
+	}
+}
+
+// SetStrict 设置严格模式：数值无法解析时 Next 返回 false 并通过 Err 报告错误
+// 默认的宽松模式只记录到 Warnings，继续解析
+func (s *Scanner) SetStrict(strict bool) {
+	s.strict = strict
+}
+
+// Warnings 返回宽松模式下收集到的解析错误
+func (s *Scanner) Warnings() []*ParseError {
+	return s.warnings
+}
+
+// Line 返回 LastTag 组码所在的行号 (二进制 DXF 为 0)
+func (s *Scanner) Line() int {
+	return s.tagLine
+}
+
+// Offset 返回已消费的字节数
+func (s *Scanner) Offset() int64 {
+	return s.counter.n - int64(s.reader.Buffered())
+}
+
+// Done 返回是否已读完所有标签，此时 LastTag 保持为最后一组标签
+func (s *Scanner) Done() bool {
+	return s.done && len(s.pending) == 0
+}
+
 func (s *Scanner) Err() error {
 	return s.err
 }
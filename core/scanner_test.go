@@ -1,6 +1,10 @@
 package core
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
 	"strings"
 	"testing"
 )
@@ -26,3 +30,142 @@ func TestScanner_Basic(t *testing.T) {
 		}
 	}
 }
+
+func TestScanner_Binary(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString(BinarySentinel)
+
+	writeCode := func(code int) {
+		_ = binary.Write(&buf, binary.LittleEndian, int16(code))
+	}
+	writeString := func(code int, value string) {
+		writeCode(code)
+		buf.WriteString(value)
+		buf.WriteByte(0)
+	}
+
+	writeString(0, "SECTION")
+	writeString(2, "ENTITIES")
+	writeString(0, "LINE")
+	writeString(8, "PJ")
+	writeCode(10)
+	_ = binary.Write(&buf, binary.LittleEndian, 1.5)
+	writeCode(70)
+	_ = binary.Write(&buf, binary.LittleEndian, int16(-2))
+	writeCode(90)
+	_ = binary.Write(&buf, binary.LittleEndian, int32(100000))
+	writeCode(160)
+	_ = binary.Write(&buf, binary.LittleEndian, int64(1)<<40)
+	writeCode(290)
+	buf.WriteByte(1)
+	writeCode(310)
+	buf.Write([]byte{2, 0xAB, 0x01})
+	writeString(0, "ENDSEC")
+
+	scanner := NewScanner(&buf)
+
+	expected := []Tag{
+		{0, "SECTION"},
+		{2, "ENTITIES"},
+		{0, "LINE"},
+		{8, "PJ"},
+		{10, "1.5"},
+		{70, "-2"},
+		{90, "100000"},
+		{160, "1099511627776"},
+		{290, "1"},
+		{310, "AB01"},
+		{0, "ENDSEC"},
+	}
+
+	for i, exp := range expected {
+		if !scanner.Next() {
+			t.Fatalf("第 %d 步读取失败: %v", i, scanner.Err())
+		}
+		if scanner.LastTag != exp {
+			t.Errorf("第 %d 步数据不符: 期望 %+v, 得到 %+v", i, exp, scanner.LastTag)
+		}
+	}
+
+	if !scanner.IsBinary() {
+		t.Error("未识别为二进制 DXF")
+	}
+	if scanner.Next() || scanner.Err() != nil {
+		t.Errorf("期望正常结束, 得到 %v", scanner.Err())
+	}
+}
+
+func TestScanner_ParseError(t *testing.T) {
+	// 第 7 行的 X 坐标无法解析，第 11 行的组码不是数字
+	dxfData := "0\nLINE\n5\n1F\n8\nPJ\n10\n1,5\n20\n2\nabc\nx\n"
+
+	scanner := NewScanner(strings.NewReader(dxfData))
+	for scanner.Next() {
+	}
+
+	warnings := scanner.Warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("期望 1 个警告, 得到 %v", warnings)
+	}
+	w := warnings[0]
+	if w.Line != 7 || w.Code != 10 || w.Value != "1,5" || w.Entity != "LINE" || w.Handle != "1F" {
+		t.Errorf("警告信息不正确: %+v", w)
+	}
+
+	var pe *ParseError
+	if !errors.As(scanner.Err(), &pe) || pe.Line != 11 || pe.Code != -1 {
+		t.Errorf("组码错误信息不正确: %v", scanner.Err())
+	}
+
+	// 严格模式：数值错误立即终止
+	scanner = NewScanner(strings.NewReader(dxfData))
+	scanner.SetStrict(true)
+	for scanner.Next() {
+	}
+	if !errors.As(scanner.Err(), &pe) || pe.Line != 7 {
+		t.Errorf("严格模式错误不正确: %v", scanner.Err())
+	}
+
+	// 文件截断：组码之后缺少值
+	scanner = NewScanner(strings.NewReader("0\nLINE\n10"))
+	for scanner.Next() {
+	}
+	if !errors.As(scanner.Err(), &pe) || pe.Line != 3 || !errors.Is(pe, io.ErrUnexpectedEOF) {
+		t.Errorf("截断错误不正确: %v", scanner.Err())
+	}
+
+	// 最后一行没有换行符仍然有效
+	scanner = NewScanner(strings.NewReader("0\nEOF"))
+	if !scanner.Next() || scanner.LastTag.Value != "EOF" || scanner.Next() || scanner.Err() != nil {
+		t.Errorf("末尾无换行解析失败: %+v %v", scanner.LastTag, scanner.Err())
+	}
+}
+
+func TestScanner_Peek(t *testing.T) {
+	dxfData := "0\nLINE\n8\n0\n10\n1\n0\nEOF\n"
+
+	scanner := NewScanner(strings.NewReader(dxfData))
+	scanner.Next()
+
+	tag, ok := scanner.Peek()
+	if !ok || tag.Code != 8 || scanner.LastTag.Value != "LINE" {
+		t.Fatalf("Peek 不正确: %+v, LastTag %+v", tag, scanner.LastTag)
+	}
+
+	tags := scanner.ReadEntity()
+	if len(tags) != 2 || tags[0].Code != 8 || tags[1].Code != 10 {
+		t.Fatalf("ReadEntity 不正确: %+v", tags)
+	}
+
+	// 下一个组码 0 没有被消费
+	if !scanner.Next() || scanner.LastTag.Value != "EOF" {
+		t.Fatalf("期望 EOF, 得到 %+v", scanner.LastTag)
+	}
+	scanner.Unread()
+	if scanner.Done() || !scanner.Next() || scanner.LastTag.Value != "EOF" {
+		t.Fatalf("Unread 后期望再次读到 EOF, 得到 %+v", scanner.LastTag)
+	}
+	if scanner.Next() || !scanner.Done() {
+		t.Error("期望读取结束")
+	}
+}
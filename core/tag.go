@@ -23,7 +23,8 @@ func (t Tag) AsInt() int {
 	return i
 }
 
-// AsString 清洗字符串（去除多余空格）
+// AsString 清洗字符串（去除多余空格），用于段名、头变量等结构性的值
+// 实体、符号表与块中的字符串组码一律保存原值 Value，保证原样写出，需要时由调用方去除空格
 func (t Tag) AsString() string {
 	return strings.TrimSpace(t.Value)
 }
@@ -0,0 +1,90 @@
+package core
+
+import "math"
+
+// Add 返回 p + q
+func (p Point) Add(q Point) Point {
+	return Point{X: p.X + q.X, Y: p.Y + q.Y, Z: p.Z + q.Z}
+}
+
+// Sub 返回 p - q
+func (p Point) Sub(q Point) Point {
+	return Point{X: p.X - q.X, Y: p.Y - q.Y, Z: p.Z - q.Z}
+}
+
+// Mul 返回 p 的 k 倍
+func (p Point) Mul(k float64) Point {
+	return Point{X: p.X * k, Y: p.Y * k, Z: p.Z * k}
+}
+
+// Dot 返回点积
+func (p Point) Dot(q Point) float64 {
+	return p.X*q.X + p.Y*q.Y + p.Z*q.Z
+}
+
+// Cross 返回叉积 p × q
+func (p Point) Cross(q Point) Point {
+	return Point{
+		X: p.Y*q.Z - p.Z*q.Y,
+		Y: p.Z*q.X - p.X*q.Z,
+		Z: p.X*q.Y - p.Y*q.X,
+	}
+}
+
+// Length 返回向量长度
+func (p Point) Length() float64 {
+	return math.Sqrt(p.Dot(p))
+}
+
+// Normalize 返回单位向量，零向量原样返回
+func (p Point) Normalize() Point {
+	if l := p.Length(); l > 0 {
+		return p.Mul(1 / l)
+	}
+	return p
+}
+
+// Extend 返回同时包含 b 与点 p 的包围盒
+func (b BBox) Extend(p Point) BBox {
+	return BBox{
+		Min: Point{X: math.Min(b.Min.X, p.X), Y: math.Min(b.Min.Y, p.Y), Z: math.Min(b.Min.Z, p.Z)},
+		Max: Point{X: math.Max(b.Max.X, p.X), Y: math.Max(b.Max.Y, p.Y), Z: math.Max(b.Max.Z, p.Z)},
+	}
+}
+
+// Bounded 判断包围盒是否有限，XLINE、RAY 等无限长的实体返回的包围盒含有无穷大
+// 合并包围盒时应跳过无限的包围盒
+func (b BBox) Bounded() bool {
+	for _, v := range []float64{b.Min.X, b.Min.Y, b.Min.Z, b.Max.X, b.Max.Y, b.Max.Z} {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// ZAxis 默认的拉伸方向 (0, 0, 1)
+var ZAxis = Point{Z: 1}
+
+// ArbitraryAxis 按 DXF 任意轴算法返回拉伸方向为 normal 的实体坐标系 (OCS) 到世界坐标系的变换
+func ArbitraryAxis(normal Point) Matrix {
+	n := normal.Normalize()
+	if n == (Point{}) || n == ZAxis {
+		return Identity()
+	}
+
+	var ax Point
+	if math.Abs(n.X) < 1.0/64 && math.Abs(n.Y) < 1.0/64 {
+		ax = Point{Y: 1}.Cross(n).Normalize()
+	} else {
+		ax = ZAxis.Cross(n).Normalize()
+	}
+	ay := n.Cross(ax).Normalize()
+
+	return Matrix{
+		{ax.X, ay.X, n.X, 0},
+		{ax.Y, ay.Y, n.Y, 0},
+		{ax.Z, ay.Z, n.Z, 0},
+		{0, 0, 0, 1},
+	}
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Writer 按 ASCII DXF 格式写出标签，并负责分配句柄
+type Writer struct {
+	writer *bufio.Writer
+	err    error
+	seed   uint64 // 下一个可用句柄
+	utf8   bool   // 字符串按 UTF-8 原样写出 (R2007 及以后)
+	owner  string // 下一个实体的所属对象，覆盖实体自身的 Owner
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		writer: bufio.NewWriter(w),
+		seed:   1,
+	}
+}
+
+// WriteTag 写出一组标签，组码按 AutoCAD 习惯右对齐为 3 位
+func (w *Writer) WriteTag(tag Tag) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = fmt.Fprintf(w.writer, "%3d\n%s\n", tag.Code, tag.Value)
+}
+
+// WriteString 写出字符串，R2007 以前的版本非 ASCII 字符转为 \U+XXXX
+func (w *Writer) WriteString(code int, value string) {
+	if !w.utf8 {
+		value = EncodeUnicode(value)
+	}
+	w.WriteTag(Tag{Code: code, Value: value})
+}
+
+// WriteInt 写出整数
+func (w *Writer) WriteInt(code int, value int) {
+	w.WriteTag(Tag{Code: code, Value: strconv.Itoa(value)})
+}
+
+// WriteFloat 写出浮点数
+func (w *Writer) WriteFloat(code int, value float64) {
+	w.WriteTag(Tag{Code: code, Value: FormatFloat(value)})
+}
+
+// WritePoint 写出三维点，组码依次为 code、code+10、code+20
+func (w *Writer) WritePoint(code int, p Point) {
+	w.WriteFloat(code, p.X)
+	w.WriteFloat(code+10, p.Y)
+	w.WriteFloat(code+20, p.Z)
+}
+
+// WritePoint2D 写出二维点，组码依次为 code、code+10
+func (w *Writer) WritePoint2D(code int, p Point) {
+	w.WriteFloat(code, p.X)
+	w.WriteFloat(code+10, p.Y)
+}
+
+// WriteLayer 写出图层 (组码 8)，空图层写为 "0"
+func (w *Writer) WriteLayer(layer string) {
+	if layer == "" {
+		layer = "0"
+	}
+	w.WriteString(8, layer)
+}
+
+// SetUTF8 设置字符串是否按 UTF-8 原样写出，R2007 (AC1021) 及以后的版本使用
+func (w *Writer) SetUTF8(utf8 bool) {
+	w.utf8 = utf8
+}
+
+// Handle 分配一个新句柄 (十六进制)
+func (w *Writer) Handle() string {
+	h := strconv.FormatUint(w.seed, 16)
+	w.seed++
+	return strings.ToUpper(h)
+}
+
+// Reserve 保留已存在的句柄，保证之后分配的句柄不会与其冲突
+func (w *Writer) Reserve(handle string) {
+	if v, err := strconv.ParseUint(strings.TrimSpace(handle), 16, 64); err == nil && v >= w.seed {
+		w.seed = v + 1
+	}
+}
+
+// SetHandleSeed 设置下一个可用句柄 ($HANDSEED)，只会增大不会减小
+func (w *Writer) SetHandleSeed(seed string) {
+	if v, err := strconv.ParseUint(strings.TrimSpace(seed), 16, 64); err == nil && v > w.seed {
+		w.seed = v
+	}
+}
+
+// SetOwner 指定下一个写出的实体的所属对象 (组码 330)，用于不修改实体而改变其所属的块记录
+func (w *Writer) SetOwner(owner string) {
+	w.owner = owner
+}
+
+// TakeOwner 返回并清除 SetOwner 指定的所属对象，由实体写出头部时调用，没有指定时为空
+func (w *Writer) TakeOwner() string {
+	owner := w.owner
+	w.owner = ""
+	return owner
+}
+
+// HandleSeed 返回下一个可用句柄，对应 $HANDSEED
+func (w *Writer) HandleSeed() string {
+	return strings.ToUpper(strconv.FormatUint(w.seed, 16))
+}
+
+// Flush 将缓冲区写入底层 io.Writer
+func (w *Writer) Flush() error {
+	if w.err == nil {
+		w.err = w.writer.Flush()
+	}
+	return w.err
+}
+
+func (w *Writer) Err() error {
+	return w.err
+}
+
+// FormatFloat 将浮点数格式化为最短的十进制表示
+func FormatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -1,146 +1,183 @@
 package dxf
 
 import (
+	"context"
 	"io"
 	"os"
 	"strings"
 
+	"golang.org/x/text/encoding"
+
 	"github.com/zooyer/dxf/core"
 	"github.com/zooyer/dxf/entities"
 )
 
-type DimStyle struct {
-	Name      string
-	Precision int     // 对应组码 271 DIMDEC，显示的小数位数
-	ExLimit   float64 // 对应组码 44 DIMEXE，标注线超出延伸线的长度
-	Scale     float64 // 对应组码 40 DIMSCALE，全局比例，影响所有标注特征)
+type Block struct {
+	Name        string
+	Handle      string     // BLOCK 的句柄
+	EndHandle   string     // ENDBLK 的句柄
+	BasePoint   core.Point // 组码 10/20/30，块基点，插入时与插入点重合
+	Flags       int        // 组码 70，1 匿名块，4 外部参照，8 外部参照覆盖，16 外部参照依赖
+	Description string     // 组码 4，块说明
+	XrefPath    string     // 组码 1，外部参照的路径
+	Entities    []entities.Entity
 }
 
-type Block struct {
-	Name     string
-	Entities []entities.Entity
+// AttDefs 返回块定义中的属性定义 ATTDEF
+func (b *Block) AttDefs() []*entities.Attrib {
+	var defs []*entities.Attrib
+	for _, e := range b.Entities {
+		if a, ok := e.(*entities.Attrib); ok && a.IsDefinition() {
+			defs = append(defs, a)
+		}
+	}
+	return defs
+}
+
+// Section 未解析的段 (如 CLASSES、OBJECTS、THUMBNAILIMAGE)，原样保留标签
+type Section struct {
+	Name string
+	Tags []core.Tag // 段内所有标签，不含 SECTION、段名与 ENDSEC
 }
 
 type Document struct {
+	Header    *Header
+	Tables    map[string]*Table // 表名 (大写) → 符号表
 	Blocks    map[string]*Block
+	Layers    map[string]*Layer // 图层名 (大写) → 图层
 	Entities  []entities.Entity
 	DimStyles map[string]*DimStyle
+	Sections  []*Section         // 按出现顺序保存的其他段
+	Warnings  []*core.ParseError // 宽松模式下解析时遇到的数值错误
+
+	// VisibleOnly 使用 WithVisibleOnly 加载时为 true，展开块参照与计算包围盒时跳过 Hidden 图层上的实体
+	VisibleOnly bool
+}
+
+// New 创建一个空文档，可在代码中添加实体后通过 Save/WriteTo 写出
+func New() *Document {
+	return &Document{
+		Header:    newHeader(),
+		Tables:    make(map[string]*Table),
+		Blocks:    make(map[string]*Block),
+		Layers:    make(map[string]*Layer),
+		Entities:  make([]entities.Entity, 0, 1024),
+		DimStyles: make(map[string]*DimStyle),
+	}
+}
+
+// Hidden 判断有效图层上的实体是否应跳过：VisibleOnly 时图层关闭或冻结返回 true，空图层名按 "0" 层处理
+func (d *Document) Hidden(layer string) bool {
+	if !d.VisibleOnly {
+		return false
+	}
+	if layer == "" {
+		layer = "0"
+	}
+	l := d.Layer(layer)
+	return l != nil && !l.Visible()
+}
+
+// Section 返回指定名称的段，不存在时返回 nil
+func (d *Document) Section(name string) *Section {
+	for _, section := range d.Sections {
+		if strings.EqualFold(section.Name, name) {
+			return section
+		}
+	}
+	return nil
+}
+
+// Block 按名称查找块定义，大小写不敏感，不存在时返回 nil
+func (d *Document) Block(name string) *Block {
+	return d.Blocks[strings.ToUpper(name)]
+}
+
+// Layer 按名称查找图层，大小写不敏感，不存在时返回 nil
+func (d *Document) Layer(name string) *Layer {
+	return d.Layers[strings.ToUpper(name)]
 }
 
 func (d *Document) parseBlocks(scanner *core.Scanner) {
 	var currentBlock *Block
+
 	for scanner.Next() {
 		tag := scanner.LastTag
-		if tag.Code == 0 && strings.ToUpper(tag.Value) == "ENDSEC" {
-			break
+		if tag.Code != 0 {
+			continue
 		}
-		if tag.Code == 0 && strings.ToUpper(tag.Value) == "BLOCK" {
+
+		switch strings.ToUpper(tag.Value) {
+		case "ENDSEC":
+			return
+		case "BLOCK":
 			currentBlock = &Block{Entities: []entities.Entity{}}
-			for scanner.Next() {
-				if scanner.LastTag.Code == 2 {
-					currentBlock.Name = strings.ToUpper(scanner.LastTag.Value)
-					break
+			for _, t := range scanner.ReadEntity() {
+				switch t.Code {
+				case 2:
+					currentBlock.Name = t.Value
+				case 5:
+					currentBlock.Handle = t.Value
+				case 10:
+					currentBlock.BasePoint.X = t.AsFloat()
+				case 20:
+					currentBlock.BasePoint.Y = t.AsFloat()
+				case 30:
+					currentBlock.BasePoint.Z = t.AsFloat()
+				case 70:
+					currentBlock.Flags = t.AsInt()
+				case 4:
+					currentBlock.Description = t.Value
+				case 1:
+					currentBlock.XrefPath = t.Value
 				}
-				if scanner.LastTag.Code == 0 {
-					break
+			}
+			d.Blocks[strings.ToUpper(currentBlock.Name)] = currentBlock
+		case "ENDBLK":
+			for _, t := range scanner.ReadEntity() {
+				if t.Code == 5 && currentBlock != nil {
+					currentBlock.EndHandle = t.Value
 				}
 			}
-			d.Blocks[currentBlock.Name] = currentBlock
-		}
-		if currentBlock != nil && tag.Code == 0 &&
-			tag.Value != "BLOCK" && tag.Value != "ENDBLK" {
+			currentBlock = nil
+		default:
 			ent := entities.CreateEntity(tag.Value)
-			if ent != nil {
-				ent.Parse(scanner)
+			_ = ent.Parse(scanner)
+			if currentBlock != nil {
 				currentBlock.Entities = append(currentBlock.Entities, ent)
 			}
 		}
 	}
 }
 
-func (d *Document) parseEntities(scanner *core.Scanner) {
-	for {
-		tag := scanner.LastTag
-		if tag.Code == 0 && strings.ToUpper(tag.Value) == "ENDSEC" {
-			break
-		}
-		if tag.Code == 0 {
-			ent := entities.CreateEntity(tag.Value)
-			if ent != nil {
-				ent.Parse(scanner)
-				d.Entities = append(d.Entities, ent)
-				continue
-			}
-		}
-		if !scanner.Next() {
-			break
-		}
-	}
-}
-func (d *Document) parseTables(scanner *core.Scanner) {
+// parseSection 原样保存未解析的段
+func (d *Document) parseSection(scanner *core.Scanner, name string) {
+	section := &Section{Name: name}
 	for scanner.Next() {
 		tag := scanner.LastTag
 		if tag.Code == 0 && strings.ToUpper(tag.Value) == "ENDSEC" {
 			break
 		}
-		if tag.Code == 0 && strings.ToUpper(tag.Value) == "TABLE" {
-			scanner.Next()
-			tableName := strings.ToUpper(scanner.LastTag.Value)
-			if tableName == "DIMSTYLE" {
-				d.parseDimStyles(scanner)
-			}
-		}
+		section.Tags = append(section.Tags, tag)
 	}
+	d.Sections = append(d.Sections, section)
 }
 
-func (d *Document) parseDimStyles(scanner *core.Scanner) {
-	var currentStyle *DimStyle
-	for {
-		tag := scanner.LastTag
-		if tag.Code == 0 && strings.ToUpper(tag.Value) == "ENDTAB" {
-			break
-		}
-
-		if tag.Code == 0 && strings.ToUpper(tag.Value) == "DIMSTYLE" {
-			currentStyle = &DimStyle{
-				Precision: 0,
-				ExLimit:   0.0,
-				Scale:     1.0, // 默认为 1.0，防止乘法归零
-			}
-
-			for scanner.Next() {
-				t := scanner.LastTag
-				if t.Code == 0 {
-					break
-				}
-				switch t.Code {
-				case 2: // 样式名称
-					currentStyle.Name = strings.ToUpper(t.Value)
-				case 271: // 精度
-					currentStyle.Precision = t.AsInt()
-				case 44: // 标注线超出延伸线长度 (DIMEXE)
-					currentStyle.ExLimit = t.AsFloat()
-				case 40: // 全局标注比例 (DIMSCALE)
-					currentStyle.Scale = t.AsFloat()
-				}
-			}
-
-			if currentStyle.Name != "" {
-				d.DimStyles[currentStyle.Name] = currentStyle
-			}
-
-			if scanner.LastTag.Code == 0 && strings.ToUpper(scanner.LastTag.Value) == "DIMSTYLE" {
-				continue
-			}
-		}
-
-		if !scanner.Next() {
-			break
-		}
+// encoding 根据图纸版本与代码页确定字符串编码
+// R2007 (AC1021) 及以后的 DXF 固定为 UTF-8，之前的版本按 $DWGCODEPAGE 编码
+func (d *Document) encoding() encoding.Encoding {
+	if version := d.Header.Version(); version >= "AC1021" {
+		return nil
 	}
+	return core.CodePageEncoding(d.Header.CodePage())
+}
+
+func Open(filename string, opts ...Option) (*Document, error) {
+	return OpenContext(context.Background(), filename, opts...)
 }
 
-func Open(filename string) (doc *Document, err error) {
+// OpenContext 打开并解析 DXF 文件，ctx 取消时停止解析并返回 ctx.Err()
+func OpenContext(ctx context.Context, filename string, opts ...Option) (doc *Document, err error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return
@@ -152,36 +189,20 @@ func Open(filename string) (doc *Document, err error) {
 		}
 	}()
 
-	return Load(file)
+	return LoadContext(ctx, file, opts...)
 }
 
-func Load(reader io.Reader) (doc *Document, err error) {
-	var (
-		scanner  = core.NewScanner(reader)
-		document = &Document{
-			Blocks:    make(map[string]*Block),
-			Entities:  make([]entities.Entity, 0, 1024),
-			DimStyles: make(map[string]*DimStyle),
-		}
-	)
-
-	for scanner.Next() {
-		tag := scanner.LastTag
-		if tag.Code == 0 && strings.ToUpper(tag.Value) == "SECTION" {
-			if !scanner.Next() {
-				break
-			}
-			sectionName := strings.ToUpper(scanner.LastTag.Value)
-			switch sectionName {
-			case "TABLES":
-				document.parseTables(scanner)
-			case "BLOCKS":
-				document.parseBlocks(scanner)
-			case "ENTITIES":
-				document.parseEntities(scanner)
-			}
-		}
-	}
+func Load(reader io.Reader, opts ...Option) (*Document, error) {
+	return LoadContext(context.Background(), reader, opts...)
+}
 
-	return document, scanner.Err()
+// LoadContext 解析 DXF，ctx 取消时停止解析并返回 ctx.Err()
+// 配合 WithProgress 可以获得读取进度
+func LoadContext(ctx context.Context, reader io.Reader, opts ...Option) (*Document, error) {
+	return walk(ctx, reader, &Visitor{
+		Entity: func(doc *Document, ent entities.Entity) error {
+			doc.Entities = append(doc.Entities, ent)
+			return nil
+		},
+	}, newOptions(opts))
 }
@@ -0,0 +1,41 @@
+package dxf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zooyer/dxf/core"
+)
+
+func TestLoad_Blocks(t *testing.T) {
+	data := "0\nSECTION\n2\nBLOCKS\n" +
+		"0\nBLOCK\n5\n20\n8\n0\n2\nDoor\n70\n0\n10\n100\n20\n50\n30\n0\n3\nDoor\n1\n\n4\n门\n" +
+		"0\nLINE\n5\n21\n8\n0\n10\n100\n20\n50\n11\n110\n21\n60\n" +
+		"0\nENDBLK\n5\n22\n8\n0\n" +
+		"0\nENDSEC\n0\nEOF\n"
+
+	doc, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := doc.Block("DOOR")
+	if block == nil || block.Name != "Door" || block.BasePoint != (core.Point{X: 100, Y: 50}) ||
+		block.Description != "门" || len(block.Entities) != 1 {
+		t.Fatalf("块定义不正确: %+v", block)
+	}
+
+	// 写出后基点与说明保持不变
+	var buf bytes.Buffer
+	if _, err = doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	again, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := again.Block("Door"); b == nil || b.BasePoint != block.BasePoint || b.Description != "门" {
+		t.Errorf("写出后块定义不一致: %+v", b)
+	}
+}
@@ -0,0 +1,103 @@
+package entities
+
+import (
+	"math"
+
+	"github.com/zooyer/dxf/core"
+)
+
+type Arc struct {
+	Circle
+	StartAngle float64 // 组码 50，起始角度 (度)，在实体坐标系中从 X 轴逆时针计算
+	EndAngle   float64 // 组码 51，终止角度 (度)
+}
+
+func init() {
+	Register("ARC", func() Entity {
+		return &Arc{Circle: Circle{BaseEntity: BaseEntity{TypeName: "ARC"}, Extrusion: core.ZAxis}}
+	})
+}
+
+// NewArc 创建一段圆弧，从 start 逆时针到 end (度)
+func NewArc(layer string, center core.Point, radius, start, end float64) *Arc {
+	a := &Arc{
+		Circle:     *NewCircle(layer, center, radius),
+		StartAngle: start,
+		EndAngle:   end,
+	}
+	a.TypeName = "ARC"
+	return a
+}
+
+func (a *Arc) Parse(s *core.Scanner) error {
+	for _, t := range s.ReadEntity() {
+		switch t.Code {
+		case 50:
+			a.StartAngle = t.AsFloat()
+		case 51:
+			a.EndAngle = t.AsFloat()
+		default:
+			a.parseTag(t)
+		}
+	}
+	return s.Err()
+}
+
+func (a *Arc) Write(w *core.Writer) error {
+	a.WriteBase(w, "AcDbCircle")
+	a.writeCircle(w)
+	w.WriteString(100, "AcDbArc")
+	w.WriteFloat(50, a.StartAngle)
+	w.WriteFloat(51, a.EndAngle)
+	a.WriteExtra(w)
+	return w.Err()
+}
+
+// sweep 返回起止角的弧度，保证 end > start
+func (a *Arc) sweep() (start, end float64) {
+	start = a.StartAngle * math.Pi / 180.0
+	return start, sweepEnd(start, a.EndAngle*math.Pi/180.0)
+}
+
+// StartPoint 返回世界坐标下的起点
+func (a *Arc) StartPoint() core.Point {
+	center, u, v := a.axes()
+	start, _ := a.sweep()
+	return center.Add(u.Mul(math.Cos(start))).Add(v.Mul(math.Sin(start)))
+}
+
+// EndPoint 返回世界坐标下的终点
+func (a *Arc) EndPoint() core.Point {
+	center, u, v := a.axes()
+	_, end := a.sweep()
+	return center.Add(u.Mul(math.Cos(end))).Add(v.Mul(math.Sin(end)))
+}
+
+// BBox 返回世界坐标下圆弧本身 (而非整圆) 的包围盒
+func (a *Arc) BBox() core.BBox {
+	center, u, v := a.axes()
+	start, end := a.sweep()
+	return conicBBox(center, u, v, start, end)
+}
+
+// Transformed 返回变换后的圆弧，非等比缩放时变为椭圆弧
+func (a *Arc) Transformed(m core.Matrix) Entity {
+	center, u, v := a.axes()
+	center, u, v = m.Apply(center), m.ApplyVector(u), m.ApplyVector(v)
+	start, end := a.sweep()
+	if !isCircular(u, v) {
+		return ellipseFrom(a.BaseEntity, center, u, v, start, end)
+	}
+
+	r := *a
+	r.Circle = *a.Circle.Transformed(m).(*Circle)
+
+	// 在新的实体坐标系中重新计算起止角
+	angle := func(t float64) float64 {
+		inv, _ := core.ArbitraryAxis(r.Extrusion).Inverse()
+		p := inv.ApplyVector(u.Mul(math.Cos(t)).Add(v.Mul(math.Sin(t))))
+		return normalizeRad(math.Atan2(p.Y, p.X)) * 180.0 / math.Pi
+	}
+	r.StartAngle, r.EndAngle = angle(start), angle(end)
+	return &r
+}
@@ -1,48 +1,286 @@
 package entities
 
-import "github.com/zooyer/dxf/core"
+import (
+	"strings"
 
+	"github.com/zooyer/dxf/core"
+)
+
+// 属性标志 (组码 70)
+const (
+	AttribInvisible = 1 // 不可见
+	AttribConstant  = 2 // 常量：值固定在 ATTDEF 中，块参照没有对应的 ATTRIB
+	AttribVerify    = 4 // 插入时需要验证
+	AttribPreset    = 8 // 预设：插入时不提示，直接使用默认值
+)
+
+// Attrib 属性，类型为 ATTRIB 时是块参照的属性值，为 ATTDEF 时是块定义中的属性定义 (Text 为默认值)
 type Attrib struct {
 	BaseEntity
-	Location core.Point
-	Tag      string // 属性标签，如 "序号"
-	Text     string // 属性值
-	Height   float64
+	Location     core.Point // 组码 10/20/30，第一对齐点
+	AlignPoint   core.Point // 组码 11/21/31，第二对齐点，非左对齐时为定位点
+	Tag          string     // 组码 2，属性标签，如 "序号"
+	Text         string     // 组码 1，属性值或默认值
+	Prompt       string     // 组码 3，插入时的提示 (仅 ATTDEF)
+	Height       float64    // 组码 40，文字高度
+	Rotation     float64    // 组码 50，旋转角度 (度)
+	WidthFactor  float64    // 组码 41，宽度因子，默认 1
+	Oblique      float64    // 组码 51，倾斜角度 (度)
+	Style        string     // 组码 7，文字样式
+	Generation   int        // 组码 71，2 表示左右反向，4 表示上下倒置
+	HAlign       int        // 组码 72，水平对齐方式
+	VAlign       int        // 组码 74，垂直对齐方式
+	Flags        int        // 组码 70，属性标志
+	FieldLength  int        // 组码 73，字段长度
+	LockPosition bool       // 组码 280，锁定位置
+	Thickness    float64    // 组码 39，厚度
+	Extrusion    core.Point // 组码 210/220/230，拉伸方向，默认 (0, 0, 1)
+	MText        *MText     // 101 嵌入对象，多行属性的多行文字，单行属性为 nil
+
+	hasAlignment bool // 是否读到了组码 11
 }
 
 func init() {
-	Register("ATTRIB", func() Entity {
-		return &Attrib{BaseEntity: BaseEntity{TypeName: "ATTRIB"}}
-	})
+	for _, name := range []string{"ATTRIB", "ATTDEF"} {
+		Register(name, func() Entity {
+			return &Attrib{BaseEntity: BaseEntity{TypeName: name}, WidthFactor: 1, Extrusion: core.ZAxis}
+		})
+	}
+}
+
+// NewAttDef 创建一个左对齐的属性定义，value 为默认值
+func NewAttDef(layer, tag, value string, location core.Point, height float64) *Attrib {
+	a := CreateEntity("ATTDEF").(*Attrib)
+	a.LayerName, a.Tag, a.Text, a.Location, a.Height = layer, tag, value, location, height
+	return a
 }
 
 func (a *Attrib) Parse(scanner *core.Scanner) error {
-	for {
-		tag := scanner.LastTag
+	var (
+		embedded []core.Tag
+		inObject bool // 是否在 101 嵌入对象中
+	)
+
+	for _, tag := range scanner.ReadEntity() {
+		if tag.Code == 101 {
+			inObject = true
+			continue
+		}
+		if inObject && tag.Code < 1000 {
+			embedded = append(embedded, tag)
+			continue
+		}
+
 		switch tag.Code {
-		case 8:
-			a.LayerName = tag.AsString()
 		case 10:
 			a.Location.X = tag.AsFloat()
 		case 20:
 			a.Location.Y = tag.AsFloat()
 		case 30:
 			a.Location.Z = tag.AsFloat()
+		case 11:
+			a.AlignPoint.X = tag.AsFloat()
+			a.hasAlignment = true
+		case 21:
+			a.AlignPoint.Y = tag.AsFloat()
+		case 31:
+			a.AlignPoint.Z = tag.AsFloat()
 		case 40:
 			a.Height = tag.AsFloat()
 		case 1:
-			a.Text = tag.AsString()
+			a.Text = tag.Value
 		case 2:
-			a.Tag = tag.AsString()
-		}
-		if !scanner.Next() || scanner.LastTag.Code == 0 {
-			break
+			a.Tag = tag.Value
+		case 3:
+			a.Prompt = tag.Value
+		case 50:
+			a.Rotation = tag.AsFloat()
+		case 41:
+			a.WidthFactor = tag.AsFloat()
+		case 51:
+			a.Oblique = tag.AsFloat()
+		case 7:
+			a.Style = tag.Value
+		case 71:
+			a.Generation = tag.AsInt()
+		case 72:
+			a.HAlign = tag.AsInt()
+		case 74:
+			a.VAlign = tag.AsInt()
+		case 70:
+			a.Flags = tag.AsInt()
+		case 73:
+			a.FieldLength = tag.AsInt()
+		case 280:
+			// 第一个 280 为属性版本，之后的为锁定位置
+			a.LockPosition = tag.AsInt() == 1
+		case 39:
+			a.Thickness = tag.AsFloat()
+		case 210:
+			a.Extrusion.X = tag.AsFloat()
+		case 220:
+			a.Extrusion.Y = tag.AsFloat()
+		case 230:
+			a.Extrusion.Z = tag.AsFloat()
+		default:
+			a.ParseTag(tag)
 		}
 	}
-	return nil
+
+	if len(embedded) > 0 {
+		a.MText = CreateEntity("MTEXT").(*MText)
+		a.MText.LayerName = a.LayerName
+		a.MText.parseTags(embedded)
+	}
+
+	return scanner.Err()
+}
+
+func (a *Attrib) Write(w *core.Writer) error {
+	a.WriteBase(w, "AcDbText")
+	if a.Thickness != 0 {
+		w.WriteFloat(39, a.Thickness)
+	}
+	w.WritePoint(10, a.Location)
+	w.WriteFloat(40, a.Height)
+	w.WriteString(1, a.Text)
+	if a.Rotation != 0 {
+		w.WriteFloat(50, a.Rotation)
+	}
+	if a.WidthFactor != 1 && a.WidthFactor != 0 {
+		w.WriteFloat(41, a.WidthFactor)
+	}
+	if a.Oblique != 0 {
+		w.WriteFloat(51, a.Oblique)
+	}
+	if a.Style != "" {
+		w.WriteString(7, a.Style)
+	}
+	if a.Generation != 0 {
+		w.WriteInt(71, a.Generation)
+	}
+	if a.HAlign != 0 {
+		w.WriteInt(72, a.HAlign)
+	}
+	if a.aligned() {
+		w.WritePoint(11, a.AlignPoint)
+	}
+	writeExtrusion(w, a.Extrusion)
+
+	if a.IsDefinition() {
+		w.WriteString(100, "AcDbAttributeDefinition")
+		w.WriteString(3, a.Prompt)
+	} else {
+		w.WriteString(100, "AcDbAttribute")
+	}
+	w.WriteString(2, a.Tag)
+	w.WriteInt(70, a.Flags)
+	if a.FieldLength != 0 {
+		w.WriteInt(73, a.FieldLength)
+	}
+	if a.VAlign != 0 {
+		w.WriteInt(74, a.VAlign)
+	}
+	if a.LockPosition {
+		w.WriteInt(280, 0)
+		w.WriteInt(280, 1)
+	}
+	if a.MText != nil {
+		w.WriteString(101, "Embedded Object")
+		a.MText.writeData(w)
+		writeTags(w, a.MText.Extra)
+	}
+	a.WriteExtra(w)
+	return w.Err()
+}
+
+// IsDefinition 是否为块定义中的属性定义 ATTDEF
+func (a *Attrib) IsDefinition() bool {
+	return strings.EqualFold(a.TypeName, "ATTDEF")
+}
+
+// Invisible 是否不可见
+func (a *Attrib) Invisible() bool {
+	return a.Flags&AttribInvisible != 0
+}
+
+// Constant 是否为常量属性
+func (a *Attrib) Constant() bool {
+	return a.Flags&AttribConstant != 0
+}
+
+// Verify 插入时是否需要验证
+func (a *Attrib) Verify() bool {
+	return a.Flags&AttribVerify != 0
+}
+
+// Preset 是否为预设属性
+func (a *Attrib) Preset() bool {
+	return a.Flags&AttribPreset != 0
+}
+
+// Multiline 是否为多行属性
+func (a *Attrib) Multiline() bool {
+	return a.MText != nil
 }
 
+// Value 返回去除格式代码与控制码的属性值，多行属性的各行以 "\n" 分隔
+func (a *Attrib) Value() string {
+	if a.MText != nil {
+		return a.MText.PlainText()
+	}
+	return DecodeSpecial(a.Text)
+}
+
+// aligned 是否使用第二对齐点定位
+func (a *Attrib) aligned() bool {
+	return a.hasAlignment || a.HAlign != 0 || a.VAlign != 0
+}
+
+// text 返回与属性外观相同的单行文字，用于计算包围盒与变换
+func (a *Attrib) text() *Text {
+	t := &Text{
+		BaseEntity:   a.BaseEntity,
+		Text:         a.Text,
+		Location:     a.Location,
+		AlignPoint:   a.AlignPoint,
+		Height:       a.Height,
+		Rotation:     a.Rotation,
+		WidthFactor:  a.WidthFactor,
+		Oblique:      a.Oblique,
+		Style:        a.Style,
+		Generation:   a.Generation,
+		HAlign:       a.HAlign,
+		VAlign:       a.VAlign,
+		Thickness:    a.Thickness,
+		Extrusion:    a.Extrusion,
+		hasAlignment: a.hasAlignment,
+	}
+	if t.WidthFactor == 0 {
+		t.WidthFactor = 1
+	}
+	if t.Extrusion == (core.Point{}) {
+		t.Extrusion = core.ZAxis
+	}
+	return t
+}
+
+// Transformed 返回变换后的属性，文字高度按比例缩放
+func (a *Attrib) Transformed(m core.Matrix) Entity {
+	c := *a
+	t := a.text().Transformed(m).(*Text)
+	c.Location, c.AlignPoint, c.Extrusion = t.Location, t.AlignPoint, t.Extrusion
+	c.Rotation, c.Height = t.Rotation, t.Height
+	if a.MText != nil {
+		c.MText = a.MText.Transformed(m).(*MText)
+	}
+	return &c
+}
+
+// BBox 按字符数估算属性文字的包围盒
 func (a *Attrib) BBox() core.BBox {
-	// 简化处理：属性文字暂时以位置点作为包围盒
-	return core.BBox{Min: a.Location, Max: a.Location}
+	if a.MText != nil {
+		return a.MText.BBox()
+	}
+	return a.text().BBox()
 }
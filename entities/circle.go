@@ -0,0 +1,109 @@
+package entities
+
+import (
+	"math"
+
+	"github.com/zooyer/dxf/core"
+)
+
+type Circle struct {
+	BaseEntity
+	Center    core.Point // 组码 10/20/30，圆心 (实体坐标系 OCS)
+	Radius    float64    // 组码 40
+	Thickness float64    // 组码 39，厚度
+	Extrusion core.Point // 组码 210/220/230，拉伸方向，默认 (0, 0, 1)
+}
+
+func init() {
+	Register("CIRCLE", func() Entity {
+		return &Circle{BaseEntity: BaseEntity{TypeName: "CIRCLE"}, Extrusion: core.ZAxis}
+	})
+}
+
+// NewCircle 创建一个圆
+func NewCircle(layer string, center core.Point, radius float64) *Circle {
+	return &Circle{
+		BaseEntity: BaseEntity{TypeName: "CIRCLE", LayerName: layer},
+		Center:     center,
+		Radius:     radius,
+		Extrusion:  core.ZAxis,
+	}
+}
+
+func (c *Circle) Parse(s *core.Scanner) error {
+	for _, t := range s.ReadEntity() {
+		c.parseTag(t)
+	}
+	return s.Err()
+}
+
+// parseTag 解析圆与圆弧共有的组码
+func (c *Circle) parseTag(t core.Tag) {
+	switch t.Code {
+	case 10:
+		c.Center.X = t.AsFloat()
+	case 20:
+		c.Center.Y = t.AsFloat()
+	case 30:
+		c.Center.Z = t.AsFloat()
+	case 40:
+		c.Radius = t.AsFloat()
+	case 39:
+		c.Thickness = t.AsFloat()
+	case 210:
+		c.Extrusion.X = t.AsFloat()
+	case 220:
+		c.Extrusion.Y = t.AsFloat()
+	case 230:
+		c.Extrusion.Z = t.AsFloat()
+	default:
+		c.ParseTag(t)
+	}
+}
+
+func (c *Circle) Write(w *core.Writer) error {
+	c.WriteBase(w, "AcDbCircle")
+	c.writeCircle(w)
+	c.WriteExtra(w)
+	return w.Err()
+}
+
+// writeCircle 写出 AcDbCircle 子类的组码
+func (c *Circle) writeCircle(w *core.Writer) {
+	if c.Thickness != 0 {
+		w.WriteFloat(39, c.Thickness)
+	}
+	w.WritePoint(10, c.Center)
+	w.WriteFloat(40, c.Radius)
+	writeExtrusion(w, c.Extrusion)
+}
+
+// axes 返回世界坐标下的圆心与两条互相垂直的半径向量
+func (c *Circle) axes() (center, u, v core.Point) {
+	ocs := core.ArbitraryAxis(c.Extrusion)
+	return ocs.Apply(c.Center), ocs.ApplyVector(core.Point{X: c.Radius}), ocs.ApplyVector(core.Point{Y: c.Radius})
+}
+
+// BBox 返回世界坐标下的包围盒
+func (c *Circle) BBox() core.BBox {
+	center, u, v := c.axes()
+	return conicBBox(center, u, v, 0, 2*math.Pi)
+}
+
+// Transformed 返回变换后的圆，非等比缩放时变为椭圆
+func (c *Circle) Transformed(m core.Matrix) Entity {
+	center, u, v := c.axes()
+	center, u, v = m.Apply(center), m.ApplyVector(u), m.ApplyVector(v)
+	if !isCircular(u, v) {
+		return ellipseFrom(c.BaseEntity, center, u, v, 0, 2*math.Pi)
+	}
+
+	r := *c
+	r.Extrusion = u.Cross(v).Normalize()
+	r.Radius = u.Length()
+	r.Thickness = c.Thickness * m.ApplyVector(c.Extrusion.Normalize()).Length()
+	if inv, ok := core.ArbitraryAxis(r.Extrusion).Inverse(); ok {
+		r.Center = inv.Apply(center)
+	}
+	return &r
+}
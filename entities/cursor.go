@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"slices"
+
+	"github.com/zooyer/dxf/core"
+)
+
+// cursor 按顺序读取实体的组码，用于 HATCH 等同一组码在不同位置含义不同的实体
+type cursor struct {
+	tags []core.Tag
+	pos  int
+}
+
+// more 是否还有未读取的组码
+func (c *cursor) more() bool {
+	return c.pos < len(c.tags)
+}
+
+// next 读取下一个组码
+func (c *cursor) next() core.Tag {
+	t := c.tags[c.pos]
+	c.pos++
+	return t
+}
+
+// peek 返回下一个组码的组码值，没有时返回 -1
+func (c *cursor) peek() int {
+	if c.more() {
+		return c.tags[c.pos].Code
+	}
+	return -1
+}
+
+// nextIf 下一个组码属于 codes 时读取它
+func (c *cursor) nextIf(codes ...int) (core.Tag, bool) {
+	if !slices.Contains(codes, c.peek()) {
+		return core.Tag{}, false
+	}
+	return c.next(), true
+}
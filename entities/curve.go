@@ -0,0 +1,116 @@
+package entities
+
+import (
+	"math"
+
+	"github.com/zooyer/dxf/core"
+)
+
+// 圆锥曲线 (圆、圆弧、椭圆) 统一表示为 center + u·cos(t) + v·sin(t)，t 为弧度
+
+// conicBBox 返回 t 从 start 逆时针扫到 end 的曲线的精确包围盒
+func conicBBox(center, u, v core.Point, start, end float64) core.BBox {
+	at := func(t float64) core.Point {
+		return center.Add(u.Mul(math.Cos(t))).Add(v.Mul(math.Sin(t)))
+	}
+
+	p := at(start)
+	box := core.BBox{Min: p, Max: p}.Extend(at(end))
+
+	// 每个坐标轴上的极值点：导数 -u·sin(t) + v·cos(t) 为 0
+	for _, axis := range [][2]float64{{u.X, v.X}, {u.Y, v.Y}, {u.Z, v.Z}} {
+		t := math.Atan2(axis[1], axis[0])
+		for _, t := range []float64{t, t + math.Pi} {
+			if inSweep(t, start, end) {
+				box = box.Extend(at(t))
+			}
+		}
+	}
+
+	return box
+}
+
+// conicPoints 将 t 从 start 到 end 的曲线离散为折线 (含起点与终点)，每段参数跨度不超过 10°
+func conicPoints(center, u, v core.Point, start, end float64) []core.Point {
+	n := int(math.Ceil(math.Abs(end-start) / (math.Pi / 18)))
+	n = max(n, 1)
+
+	points := make([]core.Point, 0, n+1)
+	for i := 0; i <= n; i++ {
+		t := start + (end-start)*float64(i)/float64(n)
+		points = append(points, center.Add(u.Mul(math.Cos(t))).Add(v.Mul(math.Sin(t))))
+	}
+	return points
+}
+
+// inSweep 判断角度 t 是否在 start 逆时针到 end 的范围内 (弧度)
+func inSweep(t, start, end float64) bool {
+	sweep := end - start
+	if sweep >= 2*math.Pi-1e-12 {
+		return true
+	}
+	return normalizeRad(t-start) <= sweep
+}
+
+// normalizeRad 将弧度规范到 [0, 2π)
+func normalizeRad(t float64) float64 {
+	t = math.Mod(t, 2*math.Pi)
+	if t < 0 {
+		t += 2 * math.Pi
+	}
+	return t
+}
+
+// sweepEnd 返回逆时针从 start 到 end 时 end 对应的弧度，保证 end > start
+func sweepEnd(start, end float64) float64 {
+	if end <= start {
+		end += 2 * math.Pi * math.Ceil((start-end)/(2*math.Pi)+1e-12)
+	}
+	return end
+}
+
+// isCircular 判断共轭半径 u、v 是否构成圆 (等长且垂直)
+func isCircular(u, v core.Point) bool {
+	lu, lv := u.Length(), v.Length()
+	eps := 1e-9 * math.Max(lu, lv)
+	return math.Abs(lu-lv) <= eps && math.Abs(u.Dot(v)) <= eps*math.Max(lu, lv)
+}
+
+// ellipseFrom 由共轭半径 u、v 构造椭圆，t 的范围 [start, end] 换算为椭圆参数
+func ellipseFrom(base BaseEntity, center, u, v core.Point, start, end float64) *Ellipse {
+	// 主轴方向：|u·cos(t) + v·sin(t)| 取极值的 t0
+	t0 := 0.5 * math.Atan2(2*u.Dot(v), u.Dot(u)-v.Dot(v))
+	major := u.Mul(math.Cos(t0)).Add(v.Mul(math.Sin(t0)))
+	minor := v.Mul(math.Cos(t0)).Sub(u.Mul(math.Sin(t0)))
+	if major.Length() < minor.Length() {
+		t0 += math.Pi / 2
+		major, minor = minor, major.Mul(-1)
+	}
+
+	e := &Ellipse{
+		BaseEntity: base,
+		Center:     center,
+		MajorAxis:  major,
+		Extrusion:  major.Cross(minor).Normalize(),
+		Ratio:      1,
+		StartParam: 0,
+		EndParam:   2 * math.Pi,
+	}
+	e.TypeName = "ELLIPSE"
+	if l := major.Length(); l > 0 {
+		e.Ratio = minor.Length() / l
+	}
+	if end-start < 2*math.Pi-1e-12 {
+		e.StartParam = normalizeRad(start - t0)
+		e.EndParam = normalizeRad(e.StartParam + (end - start))
+	}
+
+	return e
+}
+
+// writeExtrusion 写出非默认的拉伸方向
+func writeExtrusion(w *core.Writer, extrusion core.Point) {
+	if extrusion != core.ZAxis && extrusion != (core.Point{}) {
+		w.WritePoint(210, extrusion)
+	}
+}
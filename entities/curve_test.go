@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zooyer/dxf/core"
+)
+
+func nearPoint(a, b core.Point) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9 && math.Abs(a.Z-b.Z) < 1e-9
+}
+
+func TestArc_BBox(t *testing.T) {
+	// 从 45° 到 135° 的圆弧只经过 90° 的极值点
+	arc := NewArc("0", core.Point{X: 10, Y: 10}, 2, 45, 135)
+	box := arc.BBox()
+	want := core.BBox{Min: core.Point{X: 10 - math.Sqrt2, Y: 10 + math.Sqrt2}, Max: core.Point{X: 10 + math.Sqrt2, Y: 12}}
+	if !nearPoint(box.Min, want.Min) || !nearPoint(box.Max, want.Max) {
+		t.Errorf("期望 %+v, 得到 %+v", want, box)
+	}
+
+	// 跨越 0° 的圆弧
+	box = NewArc("0", core.Point{}, 1, 270, 90).BBox()
+	if !nearPoint(box.Min, core.Point{Y: -1}) || !nearPoint(box.Max, core.Point{X: 1, Y: 1}) {
+		t.Errorf("跨越 0° 的圆弧包围盒不正确: %+v", box)
+	}
+
+	// 拉伸方向为 (0,0,-1) 时 OCS 的 X 轴指向 -X
+	arc = NewArc("0", core.Point{}, 1, 0, 90)
+	arc.Extrusion = core.Point{Z: -1}
+	if p := arc.StartPoint(); !nearPoint(p, core.Point{X: -1}) {
+		t.Errorf("反向拉伸的起点不正确: %+v", p)
+	}
+}
+
+func TestEllipse_BBox(t *testing.T) {
+	// 长轴旋转 90° 的半椭圆：参数 0 在 (0,2)，π 在 (0,-2)，经过 (-1,0)
+	e := NewEllipse("0", core.Point{}, core.Point{Y: 2}, 0.5)
+	e.EndParam = math.Pi
+	box := e.BBox()
+	if !nearPoint(box.Min, core.Point{X: -1, Y: -2}) || !nearPoint(box.Max, core.Point{Y: 2}) {
+		t.Errorf("椭圆弧包围盒不正确: %+v", box)
+	}
+}
+
+func TestArc_Transformed(t *testing.T) {
+	arc := NewArc("0", core.Point{X: 1}, 1, 0, 90)
+
+	// 镜像后仍为圆弧，起止点随之镜像
+	mirrored := arc.Transformed(core.Scaling(core.Point{X: -1, Y: 1, Z: 1})).(*Arc)
+	if !nearPoint(mirrored.StartPoint(), core.Point{X: -2}) || !nearPoint(mirrored.EndPoint(), core.Point{X: -1, Y: 1}) {
+		t.Errorf("镜像圆弧不正确: %+v → %+v", mirrored.StartPoint(), mirrored.EndPoint())
+	}
+
+	// 非等比缩放后变为椭圆弧
+	e := arc.Transformed(core.Scaling(core.Point{X: 2, Y: 1, Z: 1})).(*Ellipse)
+	if !nearPoint(e.PointAt(e.StartParam), core.Point{X: 4}) || !nearPoint(e.PointAt(e.EndParam), core.Point{X: 2, Y: 1}) {
+		t.Errorf("缩放后的椭圆弧不正确: %+v → %+v", e.PointAt(e.StartParam), e.PointAt(e.EndParam))
+	}
+}
@@ -3,6 +3,7 @@ package entities
 import (
 	"math"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -11,7 +12,9 @@ import (
 
 type Dimension struct {
 	BaseEntity
+	BlockName         string     // 组码 2 (标注图形所在的匿名块，如 "*D1")
 	DimType           int        // 组码 70 (关键：区分标注类型)
+	Flags             int        // 组码 70 原值 (含类型以外的标志位)
 	StyleName         string     // 组码 3 (标注样式名称，用于关联 TABLES)
 	ActualMeasurement float64    // 组码 42
 	Text              string     // 组码 1
@@ -20,8 +23,35 @@ type Dimension struct {
 	DefPoint          core.Point // 组码 10 (标注线起点)
 	MeasureStart      core.Point // 组码 13 (被测量的起点)
 	MeasureEnd        core.Point // 组码 14 (被测量的终点)
+	ArcPoint          core.Point // 组码 15 (直径、半径标注的圆弧上的点，角度标注的顶点)
+	ArcLocation       core.Point // 组码 16 (两线角度标注的圆弧位置)
+	LeaderLength      float64    // 组码 40 (直径、半径标注的引线长度)
+
+	common   []core.Tag // AcDbDimension 子类中未解析的组码
+	subclass []core.Tag // 标注类型子类 (AcDbAlignedDimension 等) 的原始组码，含子类标记
+}
+
+// 各标注类型的子类标记与子类中由字段写出的组码
+var dimensionSubclasses = map[int]struct {
+	markers []string
+	codes   []int
+}{
+	0: {[]string{"AcDbAlignedDimension", "AcDbRotatedDimension"}, []int{13, 14, 50}},
+	1: {[]string{"AcDbAlignedDimension"}, []int{13, 14}},
+	2: {[]string{"AcDb2LineAngularDimension"}, []int{13, 14, 15, 16}},
+	3: {[]string{"AcDbDiametricDimension"}, []int{15, 40}},
+	4: {[]string{"AcDbRadialDimension"}, []int{15, 40}},
+	5: {[]string{"AcDb3PointAngularDimension"}, []int{13, 14, 15}},
+	6: {[]string{"AcDbOrdinateDimension"}, []int{13, 14}},
 }
 
+// 标注组码所在的子类
+const (
+	dimensionEntity   = iota // AcDbEntity：实体公共组码
+	dimensionCommon          // AcDbDimension
+	dimensionSubclass        // 标注类型子类
+)
+
 func init() {
 	Register("DIMENSION", func() Entity {
 		return &Dimension{BaseEntity: BaseEntity{TypeName: "DIMENSION"}}
@@ -29,16 +59,29 @@ func init() {
 }
 
 func (d *Dimension) Parse(scanner *core.Scanner) error {
-	for {
-		tag := scanner.LastTag
+	section := dimensionEntity
+	for _, tag := range scanner.ReadEntity() {
+		if tag.Code == 100 {
+			switch tag.Value {
+			case "AcDbEntity":
+			case "AcDbDimension":
+				section = dimensionCommon
+			default:
+				section = dimensionSubclass
+			}
+		}
+		if section == dimensionSubclass && tag.Code < 1000 {
+			d.subclass = append(d.subclass, tag)
+		}
+
 		switch tag.Code {
-		case 8:
-			d.LayerName = tag.AsString()
+		case 2:
+			d.BlockName = tag.Value
 		case 3:
 			// 核心：读取标注样式名称
-			d.StyleName = strings.ToUpper(tag.AsString())
+			d.StyleName = strings.ToUpper(tag.Value)
 		case 1:
-			d.Text = tag.AsString()
+			d.Text = tag.Value
 		case 42:
 			d.ActualMeasurement = tag.AsFloat()
 		case 50:
@@ -48,27 +91,136 @@ func (d *Dimension) Parse(scanner *core.Scanner) error {
 			d.DefPoint.X = tag.AsFloat()
 		case 20:
 			d.DefPoint.Y = tag.AsFloat()
+		case 30:
+			d.DefPoint.Z = tag.AsFloat()
 		case 11:
 			d.TextMidPoint.X = tag.AsFloat()
 		case 21:
 			d.TextMidPoint.Y = tag.AsFloat()
+		case 31:
+			d.TextMidPoint.Z = tag.AsFloat()
 		case 13:
 			d.MeasureStart.X = tag.AsFloat()
 		case 23:
 			d.MeasureStart.Y = tag.AsFloat()
+		case 33:
+			d.MeasureStart.Z = tag.AsFloat()
 		case 14:
 			d.MeasureEnd.X = tag.AsFloat()
 		case 24:
 			d.MeasureEnd.Y = tag.AsFloat()
+		case 34:
+			d.MeasureEnd.Z = tag.AsFloat()
+		case 15:
+			d.ArcPoint.X = tag.AsFloat()
+		case 25:
+			d.ArcPoint.Y = tag.AsFloat()
+		case 35:
+			d.ArcPoint.Z = tag.AsFloat()
+		case 16:
+			d.ArcLocation.X = tag.AsFloat()
+		case 26:
+			d.ArcLocation.Y = tag.AsFloat()
+		case 36:
+			d.ArcLocation.Z = tag.AsFloat()
+		case 40:
+			d.LeaderLength = tag.AsFloat()
 		case 70:
 			// 组码 70 包含了很多信息，我们只需要低 3 位来判定类型
-			d.DimType = tag.AsInt() & 0x07
+			d.Flags = tag.AsInt()
+			d.DimType = d.Flags & 0x07
+		default:
+			switch {
+			case section == dimensionCommon && tag.Code != 100 && tag.Code < 1000:
+				d.common = append(d.common, tag)
+			case section != dimensionSubclass || tag.Code >= 1000:
+				d.ParseTag(tag)
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+func (d *Dimension) Write(w *core.Writer) error {
+	d.WriteBase(w, "AcDbDimension")
+	if d.BlockName != "" {
+		w.WriteString(2, d.BlockName)
+	}
+	w.WritePoint(10, d.DefPoint)
+	w.WritePoint(11, d.TextMidPoint)
+	w.WriteInt(70, d.Flags&^0x07|d.DimType)
+	if d.Text != "" {
+		w.WriteString(1, d.Text)
+	}
+	style := d.StyleName
+	if style == "" {
+		style = "STANDARD"
+	}
+	w.WriteString(3, style)
+	w.WriteFloat(42, d.ActualMeasurement)
+	writeTags(w, d.common)
+	d.writeSubclass(w)
+	d.WriteExtra(w)
+	return w.Err()
+}
+
+// writeSubclass 写出标注类型的子类，未支持的类型原样写出读取到的组码
+func (d *Dimension) writeSubclass(w *core.Writer) {
+	sub, ok := dimensionSubclasses[d.DimType]
+	if !ok {
+		writeTags(w, d.subclass)
+		return
+	}
+
+	w.WriteString(100, sub.markers[0])
+	for _, code := range sub.codes {
+		switch code {
+		case 13:
+			w.WritePoint(13, d.MeasureStart)
+		case 14:
+			w.WritePoint(14, d.MeasureEnd)
+		case 15:
+			w.WritePoint(15, d.ArcPoint)
+		case 16:
+			w.WritePoint(16, d.ArcLocation)
+		case 40:
+			w.WriteFloat(40, d.LeaderLength)
+		case 50:
+			w.WriteFloat(50, d.Angle)
 		}
-		if !scanner.Next() || scanner.LastTag.Code == 0 {
-			break
+	}
+
+	// 子类中其他组码 (如倾斜角 52) 保留在字段之后
+	for _, t := range d.subclass {
+		if t.Code != 100 && !slices.Contains(sub.codes, dimensionField(t.Code)) {
+			w.WriteTag(t)
 		}
 	}
-	return nil
+	for _, marker := range sub.markers[1:] {
+		w.WriteString(100, marker)
+	}
+}
+
+// dimensionField 返回组码对应字段的首个组码，如点 13 的 Y 坐标 23 返回 13
+func dimensionField(code int) int {
+	if n := code % 10; code >= 13 && code <= 36 && n >= 3 && n <= 6 {
+		return 10 + n
+	}
+	return code
+}
+
+// Transformed 返回变换后的标注，测量值保持不变
+func (d *Dimension) Transformed(m core.Matrix) Entity {
+	c := *d
+	c.DefPoint = m.Apply(d.DefPoint)
+	c.TextMidPoint = m.Apply(d.TextMidPoint)
+	c.MeasureStart = m.Apply(d.MeasureStart)
+	c.MeasureEnd = m.Apply(d.MeasureEnd)
+	c.ArcPoint = m.Apply(d.ArcPoint)
+	c.ArcLocation = m.Apply(d.ArcLocation)
+	c.LeaderLength = d.LeaderLength * scaleOf(m)
+	c.Angle = transformAngle(m, d.Angle)
+	return &c
 }
 
 // BBox 覆盖：为了通用库的严谨性，标注的 BBox 应该包含所有定义点
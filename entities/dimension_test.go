@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zooyer/dxf/core"
+)
+
+func TestDimension_WriteSubclass(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string // 写出后子类部分的组码
+	}{
+		{
+			name: "半径标注",
+			data: "0\nDIMENSION\n5\n20\n100\nAcDbEntity\n8\nDIM\n100\nAcDbDimension\n2\n*D1\n10\n0\n20\n0\n30\n0\n11\n3\n21\n4\n31\n0\n" +
+				"70\n36\n71\n5\n3\nSTANDARD\n42\n5\n100\nAcDbRadialDimension\n15\n3\n25\n4\n35\n0\n40\n0\n",
+			want: "100\nAcDbRadialDimension\n 15\n3\n 25\n4\n 35\n0\n 40\n0\n",
+		},
+		{
+			name: "转角标注保留倾斜角",
+			data: "0\nDIMENSION\n5\n21\n100\nAcDbEntity\n8\nDIM\n100\nAcDbDimension\n2\n*D2\n10\n0\n20\n5\n30\n0\n11\n5\n21\n5\n31\n0\n" +
+				"70\n32\n71\n5\n3\nSTANDARD\n42\n10\n100\nAcDbAlignedDimension\n13\n0\n23\n0\n33\n0\n14\n10\n24\n0\n34\n0\n50\n0\n52\n30\n100\nAcDbRotatedDimension\n",
+			want: "100\nAcDbAlignedDimension\n 13\n0\n 23\n0\n 33\n0\n 14\n10\n 24\n0\n 34\n0\n 50\n0\n 52\n30\n100\nAcDbRotatedDimension\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ent, _ := parseEntity(t, tt.data)
+			var buf bytes.Buffer
+			w := core.NewWriter(&buf)
+			if err := ent.Write(w); err != nil {
+				t.Fatal(err)
+			}
+			_ = w.Flush()
+
+			out := buf.String()
+			if i := strings.Index(out, "100\nAcDbDimension\n"); i < 0 || !strings.Contains(out[i:], " 71\n5\n") {
+				t.Errorf("AcDbDimension 子类不完整:\n%s", out)
+			}
+			if strings.Count(out, "100\n") != 2+strings.Count(tt.want, "100\n") || !strings.HasSuffix(out, tt.want) {
+				t.Errorf("子类写出不正确:\n%s", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package entities
+
+import (
+	"math"
+
+	"github.com/zooyer/dxf/core"
+)
+
+type Ellipse struct {
+	BaseEntity
+	Center     core.Point // 组码 10/20/30，中心点 (世界坐标)
+	MajorAxis  core.Point // 组码 11/21/31，长轴端点相对于中心点的向量
+	Extrusion  core.Point // 组码 210/220/230，拉伸方向，默认 (0, 0, 1)
+	Ratio      float64    // 组码 40，短轴与长轴的比例
+	StartParam float64    // 组码 41，起始参数 (弧度)，整椭圆为 0
+	EndParam   float64    // 组码 42，终止参数 (弧度)，整椭圆为 2π
+}
+
+func init() {
+	Register("ELLIPSE", func() Entity {
+		return &Ellipse{BaseEntity: BaseEntity{TypeName: "ELLIPSE"}, Extrusion: core.ZAxis, Ratio: 1, EndParam: 2 * math.Pi}
+	})
+}
+
+// NewEllipse 创建一个整椭圆，majorAxis 为长轴端点相对于中心点的向量
+func NewEllipse(layer string, center, majorAxis core.Point, ratio float64) *Ellipse {
+	return &Ellipse{
+		BaseEntity: BaseEntity{TypeName: "ELLIPSE", LayerName: layer},
+		Center:     center,
+		MajorAxis:  majorAxis,
+		Extrusion:  core.ZAxis,
+		Ratio:      ratio,
+		EndParam:   2 * math.Pi,
+	}
+}
+
+func (e *Ellipse) Parse(s *core.Scanner) error {
+	for _, t := range s.ReadEntity() {
+		switch t.Code {
+		case 10:
+			e.Center.X = t.AsFloat()
+		case 20:
+			e.Center.Y = t.AsFloat()
+		case 30:
+			e.Center.Z = t.AsFloat()
+		case 11:
+			e.MajorAxis.X = t.AsFloat()
+		case 21:
+			e.MajorAxis.Y = t.AsFloat()
+		case 31:
+			e.MajorAxis.Z = t.AsFloat()
+		case 210:
+			e.Extrusion.X = t.AsFloat()
+		case 220:
+			e.Extrusion.Y = t.AsFloat()
+		case 230:
+			e.Extrusion.Z = t.AsFloat()
+		case 40:
+			e.Ratio = t.AsFloat()
+		case 41:
+			e.StartParam = t.AsFloat()
+		case 42:
+			e.EndParam = t.AsFloat()
+		default:
+			e.ParseTag(t)
+		}
+	}
+	return s.Err()
+}
+
+func (e *Ellipse) Write(w *core.Writer) error {
+	e.WriteBase(w, "AcDbEllipse")
+	w.WritePoint(10, e.Center)
+	w.WritePoint(11, e.MajorAxis)
+	writeExtrusion(w, e.Extrusion)
+	w.WriteFloat(40, e.Ratio)
+	w.WriteFloat(41, e.StartParam)
+	w.WriteFloat(42, e.EndParam)
+	e.WriteExtra(w)
+	return w.Err()
+}
+
+// axes 返回长半轴与短半轴向量
+func (e *Ellipse) axes() (u, v core.Point) {
+	normal := e.Extrusion.Normalize()
+	if normal == (core.Point{}) {
+		normal = core.ZAxis
+	}
+	return e.MajorAxis, normal.Cross(e.MajorAxis).Normalize().Mul(e.MajorAxis.Length() * e.Ratio)
+}
+
+// sweep 返回起止参数，保证 end > start
+func (e *Ellipse) sweep() (start, end float64) {
+	return e.StartParam, sweepEnd(e.StartParam, e.EndParam)
+}
+
+// PointAt 返回参数 t (弧度) 处的点
+func (e *Ellipse) PointAt(t float64) core.Point {
+	u, v := e.axes()
+	return e.Center.Add(u.Mul(math.Cos(t))).Add(v.Mul(math.Sin(t)))
+}
+
+// BBox 返回椭圆 (弧) 的精确包围盒
+func (e *Ellipse) BBox() core.BBox {
+	u, v := e.axes()
+	start, end := e.sweep()
+	return conicBBox(e.Center, u, v, start, end)
+}
+
+// Transformed 返回变换后的椭圆
+func (e *Ellipse) Transformed(m core.Matrix) Entity {
+	u, v := e.axes()
+	start, end := e.sweep()
+	return ellipseFrom(e.BaseEntity, m.Apply(e.Center), m.ApplyVector(u), m.ApplyVector(v), start, end)
+}
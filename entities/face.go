@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"github.com/zooyer/dxf/core"
+)
+
+// 三维面的不可见边标志 (组码 70)
+const (
+	FaceEdge1Invisible = 1 // 第 1 条边 (角点 1→2) 不可见
+	FaceEdge2Invisible = 2 // 第 2 条边 (角点 2→3) 不可见
+	FaceEdge3Invisible = 4 // 第 3 条边 (角点 3→4) 不可见
+	FaceEdge4Invisible = 8 // 第 4 条边 (角点 4→1) 不可见
+)
+
+// Face 三维面 3DFACE，角点按轮廓顺序排列，只有 3 个角点时第 4 个角点与第 3 个相同
+type Face struct {
+	BaseEntity
+	Corners [4]core.Point // 组码 10~13，角点 (世界坐标)
+	Flags   int           // 组码 70，不可见边标志
+}
+
+func init() {
+	Register("3DFACE", func() Entity {
+		return &Face{BaseEntity: BaseEntity{TypeName: "3DFACE"}}
+	})
+}
+
+// NewFace 创建一个三维面，只给出 3 个角点时为三角形
+func NewFace(layer string, corners ...core.Point) *Face {
+	f := &Face{BaseEntity: BaseEntity{TypeName: "3DFACE", LayerName: layer}}
+	copy(f.Corners[:], corners)
+	if len(corners) == 3 {
+		f.Corners[3] = corners[2]
+	}
+	return f
+}
+
+func (f *Face) Parse(s *core.Scanner) error {
+	for _, t := range s.ReadEntity() {
+		if parseCorner(&f.Corners, t) {
+			continue
+		}
+		switch t.Code {
+		case 70:
+			f.Flags = t.AsInt()
+		default:
+			f.ParseTag(t)
+		}
+	}
+	return s.Err()
+}
+
+func (f *Face) Write(w *core.Writer) error {
+	f.WriteBase(w, "AcDbFace")
+	for i, p := range f.Corners {
+		w.WritePoint(10+i, p)
+	}
+	if f.Flags != 0 {
+		w.WriteInt(70, f.Flags)
+	}
+	f.WriteExtra(w)
+	return w.Err()
+}
+
+// Triangle 是否为三角形 (第 3、4 个角点重合)
+func (f *Face) Triangle() bool {
+	return f.Corners[2] == f.Corners[3]
+}
+
+// EdgeVisible 第 i 条边 (从 0 开始，角点 i → i+1) 是否可见
+func (f *Face) EdgeVisible(i int) bool {
+	return f.Flags&(1<<i) == 0
+}
+
+// Transformed 返回变换后的三维面
+func (f *Face) Transformed(m core.Matrix) Entity {
+	c := *f
+	for i, p := range f.Corners {
+		c.Corners[i] = m.Apply(p)
+	}
+	return &c
+}
+
+func (f *Face) BBox() core.BBox {
+	return cornersBBox(f.Corners, core.Identity())
+}
@@ -1,15 +1,32 @@
 package entities
 
 import (
+	"strings"
+
 	"github.com/zooyer/dxf/core"
 )
 
 // Entity 是一切几何实体的接口
 type Entity interface {
+	// Parse 在 scanner 刚读到 (0, 类型名) 时调用，读取实体自身的组码 (以及 ATTRIB、VERTEX 等从属实体)
+	// 返回时下一个组码 0 尚未被消费，调用方继续 Next 即可读到它
 	Parse(scanner *core.Scanner) error
+	Write(writer *core.Writer) error
 	Type() string
 	Layer() string
 	BBox() core.BBox
+	Base() *BaseEntity
+}
+
+// Emptier 可能没有几何图形的实体 (如没有顶点的多段线)，Empty 时 BBox 返回零值包围盒
+type Emptier interface {
+	Empty() bool
+}
+
+// IsEmpty 判断实体是否没有几何图形，合并范围时应跳过这类实体
+func IsEmpty(e Entity) bool {
+	em, ok := e.(Emptier)
+	return ok && em.Empty()
 }
 
 // BaseEntity 存放所有实体通用的属性（如 Layer, Color, Handle）
@@ -17,12 +34,150 @@ type BaseEntity struct {
 	TypeName  string
 	LayerName string
 	Handle    string
+	Owner     string     // 组码 330，所属块记录或父实体的句柄
+	Extra     []core.Tag // 未解析的组码 (含 102 组与扩展数据)，按原始顺序保留，写出时原样输出
+
+	inGroup bool // 正在解析 102 {...} 组
 }
 
 func (b *BaseEntity) Type() string { return b.TypeName }
 
 func (b *BaseEntity) Layer() string { return b.LayerName }
 
+func (b *BaseEntity) Base() *BaseEntity { return b }
+
+// 特殊的 ACI 颜色号
+const (
+	ColorByBlock = 0   // 随块
+	ColorByLayer = 256 // 随层
+)
+
+// Color 返回 ACI 颜色号 (组码 62)，未设置时为 ColorByLayer
+func (b *BaseEntity) Color() int {
+	for _, tag := range b.Extra {
+		if tag.Code == 62 {
+			return tag.AsInt()
+		}
+	}
+	return ColorByLayer
+}
+
+// TrueColor 返回真彩色 0xRRGGBB (组码 420)，未设置时为 0
+func (b *BaseEntity) TrueColor() int {
+	for _, tag := range b.Extra {
+		if tag.Code == 420 {
+			return tag.AsInt()
+		}
+	}
+	return 0
+}
+
+// ParseTag 解析所有实体共有的组码，其余未识别的组码保存到 Extra
+func (b *BaseEntity) ParseTag(tag core.Tag) {
+	// 102 组 (如 {ACAD_REACTORS ... }) 内的 330 不是所属对象，整组原样保留
+	if b.inGroup || tag.Code == 102 {
+		if tag.Code == 102 {
+			b.inGroup = strings.HasPrefix(tag.Value, "{")
+		}
+		b.Extra = append(b.Extra, tag)
+		return
+	}
+
+	switch tag.Code {
+	case 0, 100:
+		// 实体类型与子类标记由 Write 重新生成
+	case 5:
+		b.Handle = tag.Value
+	case 8:
+		b.LayerName = tag.Value
+	case 330:
+		b.Owner = tag.Value
+	default:
+		b.Extra = append(b.Extra, tag)
+	}
+}
+
+// 未解析组码在写出时的位置
+const (
+	extraGroup  = iota // 102 组，位于句柄之后
+	extraEntity        // AcDbEntity 子类的公共组码 (颜色、线型等)，位于图层之后
+	extraData          // 实体数据与扩展数据，位于实体末尾
+)
+
+// extra 按写出位置筛选未解析的组码
+func (b *BaseEntity) extra(kind int) []core.Tag {
+	var (
+		tags    []core.Tag
+		inGroup bool
+	)
+
+	for _, tag := range b.Extra {
+		k := extraData
+		switch {
+		case inGroup || tag.Code == 102:
+			if tag.Code == 102 {
+				inGroup = strings.HasPrefix(tag.Value, "{")
+			}
+			k = extraGroup
+		case isEntityCode(tag.Code):
+			k = extraEntity
+		}
+		if k == kind {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// isEntityCode 判断是否为 AcDbEntity 子类中的公共组码
+func isEntityCode(code int) bool {
+	switch code {
+	case 6, 48, 60, 62, 67, 92, 160, 284, 347, 348, 370, 380, 390, 410, 420, 430, 440:
+		return true
+	}
+	return false
+}
+
+// writeTags 原样写出标签
+func writeTags(w *core.Writer, tags []core.Tag) {
+	for _, tag := range tags {
+		w.WriteString(tag.Code, tag.Value)
+	}
+}
+
+// WriteBase 写出实体头部及公共组码，subclass 为实体的子类标记 (如 "AcDbLine")，返回写出的句柄
+// 没有句柄的实体 (在代码中新建的) 在这里分配新句柄，只用于本次写出，不修改实体
+func (b *BaseEntity) WriteBase(w *core.Writer, subclass string) string {
+	handle := b.Handle
+	if handle == "" {
+		handle = w.Handle()
+	}
+	owner := w.TakeOwner()
+	if owner == "" {
+		owner = b.Owner
+	}
+
+	w.WriteString(0, b.TypeName)
+	w.WriteString(5, handle)
+	writeTags(w, b.extra(extraGroup))
+	if owner != "" {
+		w.WriteString(330, owner)
+	}
+	w.WriteString(100, "AcDbEntity")
+	w.WriteLayer(b.LayerName)
+	writeTags(w, b.extra(extraEntity))
+	if subclass != "" {
+		w.WriteString(100, subclass)
+	}
+	return handle
+}
+
+// WriteExtra 在实体末尾写出其余未解析的组码，由各实体的 Write 在最后调用
+func (b *BaseEntity) WriteExtra(w *core.Writer) {
+	writeTags(w, b.extra(extraData))
+}
+
 // EntityFactory 定义了如何从标签流中创建一个实体
 type EntityFactory func() Entity
 
@@ -33,10 +188,16 @@ func Register(typeName string, factory EntityFactory) {
 	registry[typeName] = factory
 }
 
-// CreateEntity 根据实体名称生产对应的结构体
+// CreateEntity 根据实体名称生产对应的结构体，未注册的类型返回 RawEntity
 func CreateEntity(typeName string) Entity {
 	if factory, ok := registry[typeName]; ok {
 		return factory()
 	}
-	return nil
+	return NewRawEntity(typeName)
+}
+
+// IsRegistered 判断实体类型是否已注册
+func IsRegistered(typeName string) bool {
+	_, ok := registry[typeName]
+	return ok
 }
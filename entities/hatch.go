@@ -0,0 +1,779 @@
+package entities
+
+import (
+	"math"
+
+	"github.com/zooyer/dxf/core"
+)
+
+// 填充样式 (组码 75)
+const (
+	HatchStyleNormal = 0 // 奇偶交替填充，孤岛中的孤岛再次填充
+	HatchStyleOuter  = 1 // 只填充最外层区域
+	HatchStyleIgnore = 2 // 忽略内部边界
+)
+
+// 边界路径类型标志 (组码 92)
+const (
+	HatchPathExternal  = 1  // 外部边界
+	HatchPathPolyline  = 2  // 多段线边界
+	HatchPathDerived   = 4  // 派生边界
+	HatchPathTextbox   = 8  // 文字框
+	HatchPathOutermost = 16 // 最外层边界
+)
+
+// 边界边的类型 (组码 72)
+const (
+	HatchEdgeLine    = 1 // 直线
+	HatchEdgeArc     = 2 // 圆弧
+	HatchEdgeEllipse = 3 // 椭圆弧
+	HatchEdgeSpline  = 4 // 样条曲线
+)
+
+// HatchEdge 边界路径中的一条边，坐标均为实体坐标系 (OCS) 中的二维点
+type HatchEdge struct {
+	Type int // 组码 72，边的类型
+
+	Start core.Point // 直线起点 (组码 10)
+	End   core.Point // 直线终点 (组码 11)
+
+	Center           core.Point // 圆弧、椭圆弧的圆心 (组码 10)
+	MajorAxis        core.Point // 椭圆弧长轴端点相对圆心的向量 (组码 11)
+	Radius           float64    // 圆弧半径 (组码 40)
+	Ratio            float64    // 椭圆短轴与长轴之比 (组码 40)
+	StartAngle       float64    // 组码 50，起始角度 (度)，顺时针时为取反方向上的角度
+	EndAngle         float64    // 组码 51，终止角度 (度)
+	CounterClockwise bool       // 组码 73，是否逆时针
+
+	Degree        int          // 样条阶数 (组码 94)
+	Rational      bool         // 组码 73，是否有理
+	Periodic      bool         // 组码 74，是否周期
+	Knots         []float64    // 组码 40，节点
+	ControlPoints []core.Point // 组码 10，控制点
+	Weights       []float64    // 组码 42，权重
+	FitPoints     []core.Point // 组码 11，拟合点
+	StartTangent  core.Point   // 组码 12，起点切向
+	EndTangent    core.Point   // 组码 13，终点切向
+
+	hasFit bool // 是否读到了拟合数据 (组码 97)，R2010 以上的样条边总是带有
+}
+
+// HatchPath 填充的一条边界路径，多段线边界使用 Vertices，其余使用 Edges
+type HatchPath struct {
+	Flags    int         // 组码 92，路径类型标志
+	Closed   bool        // 组码 73，多段线边界是否闭合
+	Vertices []Vertex    // 多段线边界的顶点 (组码 10、42)
+	Edges    []HatchEdge // 边界边
+	Sources  []string    // 组码 330，关联的边界对象句柄
+}
+
+// Hatch 填充，包括图案填充与实体填充
+type Hatch struct {
+	BaseEntity
+	PatternName   string       // 组码 2，图案名称，实体填充为 SOLID
+	Solid         bool         // 组码 70，是否为实体填充
+	Associative   bool         // 组码 71，是否关联边界
+	Elevation     float64      // 组码 30，标高
+	Extrusion     core.Point   // 组码 210/220/230，拉伸方向，默认 (0, 0, 1)
+	Paths         []*HatchPath // 边界路径
+	Style         int          // 组码 75，填充样式
+	PatternType   int          // 组码 76，图案类型：0 用户定义，1 预定义，2 自定义
+	PatternAngle  float64      // 组码 52，图案角度 (度)
+	PatternScale  float64      // 组码 41，图案比例或间距
+	PatternDouble bool         // 组码 77，是否双向
+	PixelSize     float64      // 组码 47，像素大小
+	Seeds         []core.Point // 组码 10/20，种子点
+
+	pattern []core.Tag // 组码 78 及图案定义线，原样保留
+}
+
+func init() {
+	Register("HATCH", func() Entity {
+		return &Hatch{BaseEntity: BaseEntity{TypeName: "HATCH"}, Extrusion: core.ZAxis, PatternScale: 1}
+	})
+}
+
+// NewSolidHatch 创建以多段线为边界的实体填充
+func NewSolidHatch(layer string, vertices ...Vertex) *Hatch {
+	return &Hatch{
+		BaseEntity:   BaseEntity{TypeName: "HATCH", LayerName: layer},
+		PatternName:  "SOLID",
+		Solid:        true,
+		Extrusion:    core.ZAxis,
+		PatternType:  1,
+		PatternScale: 1,
+		Paths:        []*HatchPath{{Flags: HatchPathExternal | HatchPathPolyline, Closed: true, Vertices: vertices}},
+	}
+}
+
+func (h *Hatch) Parse(s *core.Scanner) error {
+	c := &cursor{tags: s.ReadEntity()}
+	for c.more() {
+		t := c.next()
+		switch t.Code {
+		case 10, 20:
+			// 标高点，只有 Z 值有意义
+		case 30:
+			h.Elevation = t.AsFloat()
+		case 210:
+			h.Extrusion.X = t.AsFloat()
+		case 220:
+			h.Extrusion.Y = t.AsFloat()
+		case 230:
+			h.Extrusion.Z = t.AsFloat()
+		case 2:
+			h.PatternName = t.Value
+		case 70:
+			h.Solid = t.AsInt() == 1
+		case 71:
+			h.Associative = t.AsInt() == 1
+		case 91:
+			for n := t.AsInt(); n > 0 && c.peek() == 92; n-- {
+				h.Paths = append(h.Paths, parseHatchPath(c))
+			}
+		case 75:
+			h.Style = t.AsInt()
+		case 76:
+			h.PatternType = t.AsInt()
+		case 52:
+			h.PatternAngle = t.AsFloat()
+		case 41:
+			h.PatternScale = t.AsFloat()
+		case 77:
+			h.PatternDouble = t.AsInt() == 1
+		case 78:
+			h.pattern = append(h.pattern[:0], t)
+			for {
+				tag, ok := c.nextIf(53, 43, 44, 45, 46, 79, 49)
+				if !ok {
+					break
+				}
+				h.pattern = append(h.pattern, tag)
+			}
+		case 47:
+			h.PixelSize = t.AsFloat()
+		case 98:
+			for n := t.AsInt(); n > 0 && c.peek() == 10; n-- {
+				var seed core.Point
+				seed.X = c.next().AsFloat()
+				if tag, ok := c.nextIf(20); ok {
+					seed.Y = tag.AsFloat()
+				}
+				h.Seeds = append(h.Seeds, seed)
+			}
+		default:
+			h.ParseTag(t)
+		}
+	}
+	return s.Err()
+}
+
+// parseHatchPath 从组码 92 开始读取一条边界路径
+func parseHatchPath(c *cursor) *HatchPath {
+	p := &HatchPath{Flags: c.next().AsInt()}
+
+	if p.IsPolyline() {
+		for {
+			t, ok := c.nextIf(72, 73, 93, 10, 20, 42)
+			if !ok {
+				break
+			}
+			switch t.Code {
+			case 73:
+				p.Closed = t.AsInt() == 1
+			case 10:
+				p.Vertices = append(p.Vertices, Vertex{Point: core.Point{X: t.AsFloat()}})
+			case 20:
+				if n := len(p.Vertices); n > 0 {
+					p.Vertices[n-1].Point.Y = t.AsFloat()
+				}
+			case 42:
+				if n := len(p.Vertices); n > 0 {
+					p.Vertices[n-1].Bulge = t.AsFloat()
+				}
+			}
+		}
+	} else if t, ok := c.nextIf(93); ok {
+		for n := t.AsInt(); n > 0 && c.peek() == 72; n-- {
+			p.Edges = append(p.Edges, parseHatchEdge(c))
+		}
+	}
+
+	if t, ok := c.nextIf(97); ok {
+		for n := t.AsInt(); n > 0 && c.peek() == 330; n-- {
+			p.Sources = append(p.Sources, c.next().Value)
+		}
+	}
+
+	return p
+}
+
+// parseHatchEdge 从组码 72 开始读取一条边
+func parseHatchEdge(c *cursor) HatchEdge {
+	e := HatchEdge{Type: c.next().AsInt()}
+
+	var codes []int
+	switch e.Type {
+	case HatchEdgeLine:
+		codes = []int{10, 20, 11, 21}
+	case HatchEdgeArc:
+		codes = []int{10, 20, 40, 50, 51, 73}
+	case HatchEdgeEllipse:
+		codes = []int{10, 20, 11, 21, 40, 50, 51, 73}
+	case HatchEdgeSpline:
+		codes = []int{94, 73, 74, 95, 96, 40, 10, 20, 42, 11, 21, 12, 22, 13, 23}
+	}
+
+	for {
+		// 样条边的拟合点数量与路径末尾的边界对象数量都是组码 97，根据其后的组码区分
+		if e.Type == HatchEdgeSpline && c.peek() == 97 && c.pos+1 < len(c.tags) {
+			switch c.tags[c.pos+1].Code {
+			case 97, 11, 12, 13, 72:
+				c.next()
+				e.hasFit = true
+				continue
+			}
+		}
+
+		t, ok := c.nextIf(codes...)
+		if !ok {
+			break
+		}
+		switch t.Code {
+		case 10:
+			if e.Type == HatchEdgeSpline {
+				e.ControlPoints = append(e.ControlPoints, core.Point{X: t.AsFloat()})
+			} else {
+				e.Start.X, e.Center.X = t.AsFloat(), t.AsFloat()
+			}
+		case 20:
+			if n := len(e.ControlPoints); e.Type == HatchEdgeSpline && n > 0 {
+				e.ControlPoints[n-1].Y = t.AsFloat()
+			} else {
+				e.Start.Y, e.Center.Y = t.AsFloat(), t.AsFloat()
+			}
+		case 11:
+			if e.Type == HatchEdgeSpline {
+				e.FitPoints = append(e.FitPoints, core.Point{X: t.AsFloat()})
+			} else {
+				e.End.X, e.MajorAxis.X = t.AsFloat(), t.AsFloat()
+			}
+		case 21:
+			if n := len(e.FitPoints); e.Type == HatchEdgeSpline && n > 0 {
+				e.FitPoints[n-1].Y = t.AsFloat()
+			} else {
+				e.End.Y, e.MajorAxis.Y = t.AsFloat(), t.AsFloat()
+			}
+		case 40:
+			if e.Type == HatchEdgeSpline {
+				e.Knots = append(e.Knots, t.AsFloat())
+			} else {
+				e.Radius, e.Ratio = t.AsFloat(), t.AsFloat()
+			}
+		case 42:
+			e.Weights = append(e.Weights, t.AsFloat())
+		case 50:
+			e.StartAngle = t.AsFloat()
+		case 51:
+			e.EndAngle = t.AsFloat()
+		case 73:
+			if e.Type == HatchEdgeSpline {
+				e.Rational = t.AsInt() == 1
+			} else {
+				e.CounterClockwise = t.AsInt() == 1
+			}
+		case 74:
+			e.Periodic = t.AsInt() == 1
+		case 94:
+			e.Degree = t.AsInt()
+		case 12:
+			e.StartTangent.X = t.AsFloat()
+		case 22:
+			e.StartTangent.Y = t.AsFloat()
+		case 13:
+			e.EndTangent.X = t.AsFloat()
+		case 23:
+			e.EndTangent.Y = t.AsFloat()
+		}
+	}
+
+	return e
+}
+
+// flag 将布尔值写为 0 或 1
+func flag(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (h *Hatch) Write(w *core.Writer) error {
+	h.WriteBase(w, "AcDbHatch")
+	w.WritePoint(10, core.Point{Z: h.Elevation})
+	extrusion := h.Extrusion
+	if extrusion == (core.Point{}) {
+		extrusion = core.ZAxis
+	}
+	w.WritePoint(210, extrusion)
+	w.WriteString(2, h.PatternName)
+	w.WriteInt(70, flag(h.Solid))
+	w.WriteInt(71, flag(h.Associative))
+	w.WriteInt(91, len(h.Paths))
+	for _, p := range h.Paths {
+		p.write(w)
+	}
+	w.WriteInt(75, h.Style)
+	w.WriteInt(76, h.PatternType)
+	if !h.Solid {
+		w.WriteFloat(52, h.PatternAngle)
+		w.WriteFloat(41, h.PatternScale)
+		w.WriteInt(77, flag(h.PatternDouble))
+		if len(h.pattern) > 0 {
+			writeTags(w, h.pattern)
+		} else {
+			w.WriteInt(78, 0)
+		}
+	}
+	if h.PixelSize != 0 {
+		w.WriteFloat(47, h.PixelSize)
+	}
+	w.WriteInt(98, len(h.Seeds))
+	for _, seed := range h.Seeds {
+		w.WritePoint2D(10, seed)
+	}
+	h.WriteExtra(w)
+	return w.Err()
+}
+
+func (p *HatchPath) write(w *core.Writer) {
+	flags := p.Flags
+	if len(p.Vertices) > 0 {
+		flags |= HatchPathPolyline
+	}
+	w.WriteInt(92, flags)
+
+	if flags&HatchPathPolyline != 0 {
+		bulge := false
+		for _, v := range p.Vertices {
+			bulge = bulge || v.Bulge != 0
+		}
+		w.WriteInt(72, flag(bulge))
+		w.WriteInt(73, flag(p.Closed))
+		w.WriteInt(93, len(p.Vertices))
+		for _, v := range p.Vertices {
+			w.WritePoint2D(10, v.Point)
+			if bulge {
+				w.WriteFloat(42, v.Bulge)
+			}
+		}
+	} else {
+		w.WriteInt(93, len(p.Edges))
+		for _, e := range p.Edges {
+			e.write(w)
+		}
+	}
+
+	w.WriteInt(97, len(p.Sources))
+	for _, source := range p.Sources {
+		w.WriteString(330, source)
+	}
+}
+
+func (e *HatchEdge) write(w *core.Writer) {
+	w.WriteInt(72, e.Type)
+	switch e.Type {
+	case HatchEdgeLine:
+		w.WritePoint2D(10, e.Start)
+		w.WritePoint2D(11, e.End)
+	case HatchEdgeArc:
+		w.WritePoint2D(10, e.Center)
+		w.WriteFloat(40, e.Radius)
+		w.WriteFloat(50, e.StartAngle)
+		w.WriteFloat(51, e.EndAngle)
+		w.WriteInt(73, flag(e.CounterClockwise))
+	case HatchEdgeEllipse:
+		w.WritePoint2D(10, e.Center)
+		w.WritePoint2D(11, e.MajorAxis)
+		w.WriteFloat(40, e.Ratio)
+		w.WriteFloat(50, e.StartAngle)
+		w.WriteFloat(51, e.EndAngle)
+		w.WriteInt(73, flag(e.CounterClockwise))
+	case HatchEdgeSpline:
+		w.WriteInt(94, e.Degree)
+		w.WriteInt(73, flag(e.Rational))
+		w.WriteInt(74, flag(e.Periodic))
+		w.WriteInt(95, len(e.Knots))
+		w.WriteInt(96, len(e.ControlPoints))
+		for _, k := range e.Knots {
+			w.WriteFloat(40, k)
+		}
+		for i, p := range e.ControlPoints {
+			w.WritePoint2D(10, p)
+			if e.Rational && i < len(e.Weights) {
+				w.WriteFloat(42, e.Weights[i])
+			}
+		}
+		if e.hasFit || len(e.FitPoints) > 0 {
+			w.WriteInt(97, len(e.FitPoints))
+			for _, p := range e.FitPoints {
+				w.WritePoint2D(11, p)
+			}
+			w.WritePoint2D(12, e.StartTangent)
+			w.WritePoint2D(13, e.EndTangent)
+		}
+	}
+}
+
+// IsPolyline 是否为多段线边界
+func (p *HatchPath) IsPolyline() bool {
+	return p.Flags&HatchPathPolyline != 0
+}
+
+// IsExternal 是否为外部边界
+func (p *HatchPath) IsExternal() bool {
+	return p.Flags&(HatchPathExternal|HatchPathOutermost) != 0
+}
+
+// Polygon 返回边界的折线近似 (OCS)，圆弧按不超过 10° 离散
+func (p *HatchPath) Polygon() []core.Point {
+	var points []core.Point
+	add := func(ps ...core.Point) {
+		for _, q := range ps {
+			if n := len(points); n == 0 || points[n-1] != q {
+				points = append(points, q)
+			}
+		}
+	}
+
+	if p.IsPolyline() {
+		for s := range segments(p.Vertices, true, [2]float64{}) {
+			if !s.IsArc() {
+				add(s.Start, s.End)
+				continue
+			}
+			center, r := s.Center(), s.Radius()
+			start := math.Atan2(s.Start.Y-center.Y, s.Start.X-center.X)
+			add(conicPoints(center, core.Point{X: r}, core.Point{Y: r}, start, start+s.Angle())...)
+		}
+	} else {
+		for _, e := range p.Edges {
+			add(e.Points()...)
+		}
+	}
+
+	return points
+}
+
+// Area 返回边界围成的面积，不考虑孤岛
+func (p *HatchPath) Area() float64 {
+	return math.Abs(p.signedArea())
+}
+
+// signedArea 返回边界的有向面积，逆时针为正
+func (p *HatchPath) signedArea() float64 {
+	if p.IsPolyline() {
+		var area float64
+		for s := range segments(p.Vertices, true, [2]float64{}) {
+			area += s.area()
+		}
+		return area
+	}
+
+	var area float64
+	for _, e := range p.Edges {
+		area += e.area()
+	}
+	return area
+}
+
+// conic 返回圆弧、椭圆弧的 center + u·cos(t) + v·sin(t) 表示，t 从 start 逆时针到 end
+// 顺时针的边 v 取反，沿 t 增大的方向即为边的方向
+func (e *HatchEdge) conic() (center, u, v core.Point, start, end float64) {
+	center = e.Center
+	if e.Type == HatchEdgeArc {
+		u, v = core.Point{X: e.Radius}, core.Point{Y: e.Radius}
+	} else {
+		u, v = e.MajorAxis, core.Point{X: -e.MajorAxis.Y, Y: e.MajorAxis.X}.Mul(e.Ratio)
+	}
+	if !e.CounterClockwise {
+		v = v.Mul(-1)
+	}
+
+	start, end = e.StartAngle*math.Pi/180.0, e.EndAngle*math.Pi/180.0
+	if math.Abs(e.EndAngle-e.StartAngle) >= 360 {
+		return center, u, v, 0, 2 * math.Pi
+	}
+	if e.Type == HatchEdgeEllipse {
+		// 椭圆弧的角度换算为参数
+		start, end = angleToParam(start, e.Ratio), angleToParam(end, e.Ratio)
+	}
+	return center, u, v, start, sweepEnd(start, end)
+}
+
+// angleToParam 将椭圆上的角度换算为参数
+func angleToParam(angle, ratio float64) float64 {
+	if ratio == 0 {
+		return angle
+	}
+	return math.Atan2(math.Sin(angle)/ratio, math.Cos(angle))
+}
+
+// paramToAngle 将椭圆的参数换算为角度
+func paramToAngle(param, ratio float64) float64 {
+	return math.Atan2(ratio*math.Sin(param), math.Cos(param))
+}
+
+// Points 返回边的折线近似 (含起点与终点)
+func (e *HatchEdge) Points() []core.Point {
+	switch e.Type {
+	case HatchEdgeLine:
+		return []core.Point{e.Start, e.End}
+	case HatchEdgeArc, HatchEdgeEllipse:
+		return conicPoints(e.conic())
+	case HatchEdgeSpline:
+		var weights []float64
+		if e.Rational {
+			weights = e.Weights
+		}
+		if c := newNURBS(e.Degree, e.Knots, e.ControlPoints, weights); c != nil {
+			return c.flatten(0)
+		}
+		// 只有拟合点的样条边按拟合点连线近似
+		return e.FitPoints
+	}
+	return nil
+}
+
+// area 返回边与原点围成的有向面积 (格林公式)
+func (e *HatchEdge) area() float64 {
+	cross := func(a, b core.Point) float64 { return a.X*b.Y - a.Y*b.X }
+
+	switch e.Type {
+	case HatchEdgeArc, HatchEdgeEllipse:
+		c, u, v, a, b := e.conic()
+		return (cross(c, u)*(math.Cos(b)-math.Cos(a)) + cross(c, v)*(math.Sin(b)-math.Sin(a)) + cross(u, v)*(b-a)) / 2
+	}
+
+	var area float64
+	points := e.Points()
+	for i := 1; i < len(points); i++ {
+		area += cross(points[i-1], points[i]) / 2
+	}
+	return area
+}
+
+// bbox 返回边经过 m 变换后的包围盒，z 为标高
+func (e *HatchEdge) bbox(m core.Matrix, z float64) core.BBox {
+	if e.Type == HatchEdgeArc || e.Type == HatchEdgeEllipse {
+		c, u, v, a, b := e.conic()
+		c.Z = z
+		return conicBBox(m.Apply(c), m.ApplyVector(u), m.ApplyVector(v), a, b)
+	}
+
+	var box core.BBox
+	for i, p := range e.Points() {
+		p.Z = z
+		if p = m.Apply(p); i == 0 {
+			box = core.BBox{Min: p, Max: p}
+		} else {
+			box = box.Extend(p)
+		}
+	}
+	return box
+}
+
+// Area 返回填充的净面积：按边界的嵌套层次与填充样式扣除孤岛
+func (h *Hatch) Area() float64 {
+	polygons := make([][]core.Point, len(h.Paths))
+	areas := make([]float64, len(h.Paths))
+	for i, p := range h.Paths {
+		polygons[i], areas[i] = p.Polygon(), p.Area()
+	}
+
+	var total float64
+	for i, polygon := range polygons {
+		if len(polygon) == 0 {
+			continue
+		}
+
+		// 嵌套层次：包含该边界的其他 (更大的) 边界数量
+		depth := 0
+		for j, other := range polygons {
+			if j != i && areas[j] > areas[i] && pointInPolygon(polygon[0], other) {
+				depth++
+			}
+		}
+
+		switch {
+		case depth == 0:
+			total += areas[i]
+		case h.Style == HatchStyleIgnore:
+		case h.Style == HatchStyleOuter && depth > 1:
+		case depth%2 == 1:
+			total -= areas[i]
+		default:
+			total += areas[i]
+		}
+	}
+
+	return math.Max(total, 0)
+}
+
+// pointInPolygon 射线法判断点是否在多边形内
+func pointInPolygon(p core.Point, polygon []core.Point) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Empty 是否没有边界
+func (h *Hatch) Empty() bool {
+	for _, p := range h.Paths {
+		if len(p.Vertices) > 0 || len(p.Edges) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// BBox 返回世界坐标下所有边界的包围盒
+func (h *Hatch) BBox() core.BBox {
+	var (
+		m     = core.ArbitraryAxis(h.Extrusion)
+		box   core.BBox
+		empty = true
+	)
+
+	extend := func(b core.BBox) {
+		if empty {
+			box, empty = b, false
+			return
+		}
+		box = box.Extend(b.Min).Extend(b.Max)
+	}
+
+	for _, p := range h.Paths {
+		if p.IsPolyline() {
+			if len(p.Vertices) == 0 {
+				continue
+			}
+			vertices := make([]Vertex, len(p.Vertices))
+			for i, v := range p.Vertices {
+				vertices[i] = v
+				vertices[i].Point.Z = h.Elevation
+			}
+			extend(polylineBBox(vertices, true, m))
+			continue
+		}
+		for _, e := range p.Edges {
+			extend(e.bbox(m, h.Elevation))
+		}
+	}
+
+	return box
+}
+
+// Transformed 返回变换后的填充，非等比缩放时圆弧边变为椭圆弧边
+func (h *Hatch) Transformed(m core.Matrix) Entity {
+	c := *h
+	m, c.Extrusion = ocsTransform(m, h.Extrusion)
+	c.Elevation = m.Apply(core.Point{Z: h.Elevation}).Z
+	mirror := m.Determinant() < 0
+
+	// point 变换 OCS 中的二维点
+	point := func(p core.Point) core.Point {
+		p.Z = h.Elevation
+		p = m.Apply(p)
+		p.Z = 0
+		return p
+	}
+	vector := func(v core.Point) core.Point {
+		v = m.ApplyVector(v)
+		v.Z = 0
+		return v
+	}
+
+	c.Paths = make([]*HatchPath, len(h.Paths))
+	for i, p := range h.Paths {
+		path := *p
+		path.Vertices = make([]Vertex, len(p.Vertices))
+		for j, v := range p.Vertices {
+			v.Point = point(v.Point)
+			if mirror {
+				v.Bulge = -v.Bulge
+			}
+			path.Vertices[j] = v
+		}
+		path.Edges = make([]HatchEdge, len(p.Edges))
+		for j, e := range p.Edges {
+			path.Edges[j] = e.transformed(point, vector)
+		}
+		c.Paths[i] = &path
+	}
+
+	c.Seeds = make([]core.Point, len(h.Seeds))
+	for i, seed := range h.Seeds {
+		c.Seeds[i] = point(seed)
+	}
+	c.PatternAngle = transformAngle(m, h.PatternAngle)
+	c.PatternScale = h.PatternScale * scaleOf(m)
+
+	return &c
+}
+
+// transformed 返回变换后的边，point、vector 分别变换点与向量
+func (e HatchEdge) transformed(point, vector func(core.Point) core.Point) HatchEdge {
+	switch e.Type {
+	case HatchEdgeLine:
+		e.Start, e.End = point(e.Start), point(e.End)
+	case HatchEdgeArc, HatchEdgeEllipse:
+		center, u, v, start, end := e.conic()
+		center, u, v = point(center), vector(u), vector(v)
+		full := end-start >= 2*math.Pi-1e-12
+
+		el := ellipseFrom(BaseEntity{}, center, u, v, start, end)
+		sweep := end - start
+		e.Center = center
+		e.CounterClockwise = el.Extrusion.Z >= 0
+
+		if e.Type == HatchEdgeArc && isCircular(u, v) {
+			// 仍为圆弧：参数加上 (逆时针) 或减去 (顺时针) 长轴方向角
+			phi := math.Atan2(el.MajorAxis.Y, el.MajorAxis.X)
+			if !e.CounterClockwise {
+				phi = -phi
+			}
+			e.Radius = el.MajorAxis.Length()
+			e.StartAngle = normalizeRad(el.StartParam+phi) * 180.0 / math.Pi
+			e.EndAngle = e.StartAngle + sweep*180.0/math.Pi
+			if full {
+				e.StartAngle, e.EndAngle = 0, 360
+			}
+			break
+		}
+
+		e.Type = HatchEdgeEllipse
+		e.MajorAxis, e.Ratio = el.MajorAxis, el.Ratio
+		e.StartAngle = normalizeRad(paramToAngle(el.StartParam, el.Ratio)) * 180.0 / math.Pi
+		e.EndAngle = normalizeRad(paramToAngle(el.StartParam+sweep, el.Ratio)) * 180.0 / math.Pi
+		if full {
+			e.StartAngle, e.EndAngle = 0, 360
+		}
+	case HatchEdgeSpline:
+		transform := func(points []core.Point) []core.Point {
+			result := make([]core.Point, len(points))
+			for i, p := range points {
+				result[i] = point(p)
+			}
+			return result
+		}
+		e.ControlPoints, e.FitPoints = transform(e.ControlPoints), transform(e.FitPoints)
+		e.StartTangent, e.EndTangent = vector(e.StartTangent), vector(e.EndTangent)
+	}
+	return e
+}
@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/zooyer/dxf/core"
+)
+
+// 10x10 的正方形玻璃 (直线边)，中间有一个半径 2 的圆形孤岛 (圆弧边)，以及一个样条边
+const hatchData = "0\nHATCH\n5\n40\n8\nGLASS\n100\nAcDbHatch\n10\n0\n20\n0\n30\n0\n210\n0\n220\n0\n230\n1\n" +
+	"2\nANSI31\n70\n0\n71\n1\n91\n3\n" +
+	"92\n1\n93\n4\n" +
+	"72\n1\n10\n0\n20\n0\n11\n10\n21\n0\n" +
+	"72\n1\n10\n10\n20\n0\n11\n10\n21\n10\n" +
+	"72\n1\n10\n10\n20\n10\n11\n0\n21\n10\n" +
+	"72\n1\n10\n0\n20\n10\n11\n0\n21\n0\n" +
+	"97\n1\n330\n3F\n" +
+	"92\n16\n93\n1\n72\n2\n10\n5\n20\n5\n40\n2\n50\n0\n51\n360\n73\n0\n97\n0\n" +
+	"92\n0\n93\n1\n72\n4\n94\n3\n73\n0\n74\n0\n95\n8\n96\n4\n" +
+	"40\n0\n40\n0\n40\n0\n40\n0\n40\n1\n40\n1\n40\n1\n40\n1\n" +
+	"10\n20\n20\n0\n10\n21\n20\n1\n10\n22\n20\n1\n10\n23\n20\n0\n" +
+	"97\n0\n12\n1\n22\n1\n13\n1\n23\n-1\n97\n0\n" +
+	"75\n1\n76\n1\n52\n45\n41\n2\n77\n0\n78\n1\n53\n45\n43\n0\n44\n0\n45\n-2.2\n46\n2.2\n79\n0\n" +
+	"98\n1\n10\n1\n20\n1\n450\n0\n0\nEOF\n"
+
+func TestHatch_Parse(t *testing.T) {
+	ent, _ := parseEntity(t, hatchData)
+	h := ent.(*Hatch)
+
+	if h.PatternName != "ANSI31" || h.Solid || !h.Associative || len(h.Paths) != 3 || h.Style != HatchStyleOuter {
+		t.Fatalf("填充解析不正确: %+v", h)
+	}
+	if p := h.Paths[0]; len(p.Edges) != 4 || len(p.Sources) != 1 || p.Sources[0] != "3F" || !p.IsExternal() {
+		t.Errorf("外边界不正确: %+v", p)
+	}
+	if e := h.Paths[1].Edges[0]; e.Type != HatchEdgeArc || e.Radius != 2 || e.Center != (core.Point{X: 5, Y: 5}) {
+		t.Errorf("圆弧边不正确: %+v", e)
+	}
+	if e := h.Paths[2].Edges[0]; len(e.Knots) != 8 || len(e.ControlPoints) != 4 || !e.hasFit || e.EndTangent.Y != -1 {
+		t.Errorf("样条边不正确: %+v", e)
+	}
+	if len(h.Seeds) != 1 || h.PatternAngle != 45 || h.PatternScale != 2 {
+		t.Errorf("图案参数不正确: %+v", h)
+	}
+
+	// 样条边界与正方形不相交，不是孤岛
+	if box := h.BBox(); box.Max.X != 23 || box.Max.Y != 10 {
+		t.Errorf("包围盒不正确: %+v", box)
+	}
+
+	var buf bytes.Buffer
+	w := core.NewWriter(&buf)
+	if err := h.Write(w); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Flush()
+	again, _ := parseEntity(t, buf.String())
+	q := again.(*Hatch)
+	if len(q.Paths) != 3 || len(q.Paths[2].Edges[0].ControlPoints) != 4 || len(q.pattern) != 7 || len(q.Seeds) != 1 {
+		t.Errorf("写出后重新解析不正确:\n%s", buf.String())
+	}
+}
+
+func TestHatch_Area(t *testing.T) {
+	ent, _ := parseEntity(t, hatchData)
+	h := ent.(*Hatch)
+	h.Paths = h.Paths[:2]
+
+	want := 100 - 4*math.Pi
+	if area := h.Area(); math.Abs(area-want) > 1e-9 {
+		t.Errorf("期望面积 %v, 得到 %v", want, area)
+	}
+
+	// 等比缩放后面积为 4 倍，非等比缩放后圆变为椭圆
+	if area := h.Transformed(core.Scaling(core.Point{X: 2, Y: 2, Z: 1})).(*Hatch).Area(); math.Abs(area-4*want) > 1e-9 {
+		t.Errorf("缩放后的面积不正确: %v", area)
+	}
+	c := h.Transformed(core.Scaling(core.Point{X: -2, Y: 1, Z: 1})).(*Hatch)
+	if e := c.Paths[1].Edges[0]; e.Type != HatchEdgeEllipse {
+		t.Errorf("非等比缩放后应为椭圆弧边: %+v", e)
+	}
+	if area := c.Area(); math.Abs(area-2*want) > 1e-9 {
+		t.Errorf("镜像缩放后的面积不正确: %v", area)
+	}
+
+	// 带凸度的多段线边界：2x1 的矩形加半圆拱
+	solid := NewSolidHatch("0",
+		Vertex{Point: core.Point{}}, Vertex{Point: core.Point{X: 2}},
+		Vertex{Point: core.Point{X: 2, Y: 1}, Bulge: 1}, Vertex{Point: core.Point{Y: 1}})
+	if area := solid.Area(); math.Abs(area-(2+math.Pi/2)) > 1e-9 {
+		t.Errorf("多段线边界面积不正确: %v", area)
+	}
+	if box := solid.BBox(); math.Abs(box.Max.Y-2) > 1e-9 {
+		t.Errorf("多段线边界包围盒不正确: %+v", box)
+	}
+}
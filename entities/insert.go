@@ -1,36 +1,74 @@
 package entities
 
-import "github.com/zooyer/dxf/core"
+import (
+	"iter"
+	"slices"
+	"strings"
 
+	"github.com/zooyer/dxf/core"
+)
+
+// Insert 块参照 INSERT，行列数大于 1 时按行列阵列插入 (子类 AcDbMInsertBlock)
 type Insert struct {
 	BaseEntity
 	BlockName      string
 	InsertionPoint core.Point
 	Scale          core.Point
 	Rotation       float64
+	Columns        int        // 组码 70，阵列列数，默认 1
+	Rows           int        // 组码 71，阵列行数，默认 1
+	ColumnSpacing  float64    // 组码 44，列间距
+	RowSpacing     float64    // 组码 45，行间距
+	Extrusion      core.Point // 组码 210/220/230，拉伸方向，默认 (0, 0, 1)，插入点与旋转均在其 OCS 中
 	Attributes     []*Attrib
+	SeqEnd         string // 属性结束标记 SEQEND 的句柄
 }
 
 func init() {
-	Register("INSERT", func() Entity {
-		return &Insert{
-			BaseEntity: BaseEntity{TypeName: "INSERT"},
-			Scale:      core.Point{X: 1, Y: 1, Z: 1}, // 默认缩放为 1
-			Attributes: []*Attrib{},
-		}
-	})
+	// DXF 中阵列块参照的类型也是 INSERT，读取时兼容写成 MINSERT 的文件
+	for _, name := range []string{"INSERT", "MINSERT"} {
+		Register(name, func() Entity {
+			return &Insert{
+				BaseEntity: BaseEntity{TypeName: "INSERT"},
+				Scale:      core.Point{X: 1, Y: 1, Z: 1}, // 默认缩放为 1
+				Columns:    1,
+				Rows:       1,
+				Extrusion:  core.ZAxis,
+				Attributes: []*Attrib{},
+			}
+		})
+	}
+}
+
+// NewInsert 创建一个块参照
+func NewInsert(layer, blockName string, point core.Point) *Insert {
+	return &Insert{
+		BaseEntity:     BaseEntity{TypeName: "INSERT", LayerName: layer},
+		BlockName:      blockName,
+		InsertionPoint: point,
+		Scale:          core.Point{X: 1, Y: 1, Z: 1},
+		Columns:        1,
+		Rows:           1,
+		Extrusion:      core.ZAxis,
+		Attributes:     []*Attrib{},
+	}
+}
+
+// NewMInsert 创建一个 columns 列、rows 行的阵列块参照，间距沿块参照旋转后的 X、Y 方向
+func NewMInsert(layer, blockName string, point core.Point, columns, rows int, columnSpacing, rowSpacing float64) *Insert {
+	i := NewInsert(layer, blockName, point)
+	i.Columns, i.Rows = columns, rows
+	i.ColumnSpacing, i.RowSpacing = columnSpacing, rowSpacing
+	return i
 }
 
 func (i *Insert) Parse(scanner *core.Scanner) error {
 	hasAttributes := false
 
-	for {
-		tag := scanner.LastTag
+	for _, tag := range scanner.ReadEntity() {
 		switch tag.Code {
 		case 2:
-			i.BlockName = tag.AsString()
-		case 8:
-			i.LayerName = tag.AsString()
+			i.BlockName = tag.Value
 		case 10:
 			i.InsertionPoint.X = tag.AsFloat()
 		case 20:
@@ -45,44 +83,176 @@ func (i *Insert) Parse(scanner *core.Scanner) error {
 			i.Scale.Z = tag.AsFloat()
 		case 50:
 			i.Rotation = tag.AsFloat()
+		case 70:
+			i.Columns = tag.AsInt()
+		case 71:
+			i.Rows = tag.AsInt()
+		case 44:
+			i.ColumnSpacing = tag.AsFloat()
+		case 45:
+			i.RowSpacing = tag.AsFloat()
+		case 210:
+			i.Extrusion.X = tag.AsFloat()
+		case 220:
+			i.Extrusion.Y = tag.AsFloat()
+		case 230:
+			i.Extrusion.Z = tag.AsFloat()
 		case 66:
 			if tag.AsInt() == 1 {
 				hasAttributes = true
 			}
+		default:
+			i.ParseTag(tag)
 		}
+	}
+
+	if !hasAttributes {
+		return scanner.Err()
+	}
 
-		if !scanner.Next() || scanner.LastTag.Code == 0 {
+	// 属性紧跟在 INSERT 之后，以 SEQEND 结束；遇到其他实体说明文件缺少 SEQEND，留给调用方处理
+	for {
+		tag, ok := scanner.Peek()
+		if !ok || tag.Code != 0 {
 			break
 		}
-	}
 
-	// 核心逻辑：如果标记了有属性，则继续在当前流中抓取 ATTRIB 直到 SEQEND
-	if hasAttributes {
-		for {
-			tag := scanner.LastTag
-			if tag.Code == 0 {
-				if tag.Value == "SEQEND" {
-					scanner.Next() // 消耗掉 SEQEND
-					break
+		switch strings.ToUpper(tag.Value) {
+		case "ATTRIB":
+			scanner.Next()
+			attr := CreateEntity(tag.Value).(*Attrib)
+			if err := attr.Parse(scanner); err != nil {
+				return err
+			}
+			i.Attributes = append(i.Attributes, attr)
+			continue
+		case "SEQEND":
+			scanner.Next()
+			for _, t := range scanner.ReadEntity() {
+				if t.Code == 5 {
+					i.SeqEnd = t.Value
 				}
-				// 创建子实体
-				subEntity := CreateEntity(tag.Value)
-				if attr, ok := subEntity.(*Attrib); ok {
-					attr.Parse(scanner)
-					i.Attributes = append(i.Attributes, attr)
-					continue // Parse 内部已经 Next 了，直接进入下一次判断
+			}
+		}
+		break
+	}
+
+	return scanner.Err()
+}
+
+func (i *Insert) Write(w *core.Writer) error {
+	subclass := "AcDbBlockReference"
+	if i.IsArray() {
+		subclass = "AcDbMInsertBlock"
+	}
+	handle := i.WriteBase(w, subclass)
+	if len(i.Attributes) > 0 {
+		w.WriteInt(66, 1)
+	}
+	w.WriteString(2, i.BlockName)
+	w.WritePoint(10, i.InsertionPoint)
+	w.WriteFloat(41, i.Scale.X)
+	w.WriteFloat(42, i.Scale.Y)
+	w.WriteFloat(43, i.Scale.Z)
+	w.WriteFloat(50, i.Rotation)
+	if i.IsArray() {
+		w.WriteInt(70, i.columns())
+		w.WriteInt(71, i.rows())
+		w.WriteFloat(44, i.ColumnSpacing)
+		w.WriteFloat(45, i.RowSpacing)
+	}
+	writeExtrusion(w, i.Extrusion)
+	i.WriteExtra(w)
+
+	if len(i.Attributes) == 0 {
+		return w.Err()
+	}
+
+	// 属性跟随在 INSERT 之后，以 SEQEND 结束，所属对象只写入副本
+	for _, attr := range i.Attributes {
+		a := *attr
+		a.Owner = handle
+		if err := a.Write(w); err != nil {
+			return err
+		}
+	}
+
+	seqEnd := i.SeqEnd
+	if seqEnd == "" {
+		seqEnd = w.Handle()
+	}
+	w.WriteString(0, "SEQEND")
+	w.WriteString(5, seqEnd)
+	w.WriteString(330, handle)
+	w.WriteString(100, "AcDbEntity")
+	w.WriteLayer(i.LayerName)
+
+	return w.Err()
+}
+
+// Transform 返回块坐标到插入所在坐标系的变换矩阵，base 为所插入块的基点
+// 依次为：减去基点 → 缩放 (可为负数镜像) → 绕 Z 轴旋转 → 平移到插入点 → 由 OCS 转换到 WCS
+func (i *Insert) Transform(base core.Point) core.Matrix {
+	return i.cell(base, 0, 0)
+}
+
+// Transforms 依次产出阵列中每个单元的变换矩阵 (逐行排列)，普通块参照只有一个，与 Transform 相同
+// 阵列的偏移沿旋转后的 X、Y 方向，不受缩放影响；行列数来自文件，可能很大，按需逐个计算而不预先分配
+func (i *Insert) Transforms(base core.Point) iter.Seq[core.Matrix] {
+	return func(yield func(core.Matrix) bool) {
+		for row := range i.rows() {
+			for col := range i.columns() {
+				if !yield(i.cell(base, col, row)) {
+					return
 				}
 			}
-			if !scanner.Next() {
-				break
+		}
+	}
+}
+
+// CornerTransforms 返回阵列四个角上单元的变换矩阵 (去除重复)
+// 各单元只相差一个平移，阵列的合并包围盒等于这几个单元包围盒的合并
+func (i *Insert) CornerTransforms(base core.Point) []core.Matrix {
+	var result []core.Matrix
+	for _, row := range []int{0, i.rows() - 1} {
+		for _, col := range []int{0, i.columns() - 1} {
+			if m := i.cell(base, col, row); !slices.Contains(result, m) {
+				result = append(result, m)
 			}
 		}
 	}
-	return nil
+	return result
+}
+
+// cell 返回第 row 行、第 col 列单元的变换矩阵
+func (i *Insert) cell(base core.Point, col, row int) core.Matrix {
+	offset := core.Point{X: float64(col) * i.ColumnSpacing, Y: float64(row) * i.RowSpacing}
+	return core.ArbitraryAxis(i.Extrusion).
+		Multiply(core.Translation(i.InsertionPoint)).
+		Multiply(core.RotationZ(i.Rotation)).
+		Multiply(core.Translation(offset)).
+		Multiply(core.Scaling(i.Scale)).
+		Multiply(core.Translation(core.Point{X: -base.X, Y: -base.Y, Z: -base.Z}))
+}
+
+// IsArray 是否为阵列块参照 (行列数大于 1)
+func (i *Insert) IsArray() bool {
+	return i.columns() > 1 || i.rows() > 1
+}
+
+// columns 返回阵列列数，未设置时为 1
+func (i *Insert) columns() int {
+	return max(i.Columns, 1)
+}
+
+// rows 返回阵列行数，未设置时为 1
+func (i *Insert) rows() int {
+	return max(i.Rows, 1)
 }
 
 func (i *Insert) BBox() core.BBox {
 	// Insert 的包围盒比较特殊，通常需要结合 Block 定义计算
-	// 这里先返回插入点
-	return core.BBox{Min: i.InsertionPoint, Max: i.InsertionPoint}
+	// 这里先返回插入点 (WCS)
+	p := core.ArbitraryAxis(i.Extrusion).Apply(i.InsertionPoint)
+	return core.BBox{Min: p, Max: p}
 }
@@ -0,0 +1,196 @@
+package entities
+
+import (
+	"github.com/zooyer/dxf/core"
+)
+
+// 引线的注释类型 (LEADER 组码 73)
+const (
+	LeaderAnnotationMText     = 0 // 多行文字
+	LeaderAnnotationTolerance = 1 // 公差
+	LeaderAnnotationBlock     = 2 // 块参照
+	LeaderAnnotationNone      = 3 // 无注释
+)
+
+// Leader 引线 LEADER，第一个顶点为箭头位置，注释对象通过句柄关联
+type Leader struct {
+	BaseEntity
+	StyleName           string       // 组码 3，标注样式
+	Arrowhead           bool         // 组码 71，是否有箭头
+	PathType            int          // 组码 72，路径类型：0 直线，1 样条
+	AnnotationType      int          // 组码 73，注释类型
+	HooklineDirection   int          // 组码 74，钩线方向与水平方向相反时为 1
+	Hookline            bool         // 组码 75，是否有钩线
+	TextHeight          float64      // 组码 40，文字注释高度
+	TextWidth           float64      // 组码 41，文字注释宽度
+	Vertices            []core.Point // 组码 10/20/30，顶点 (世界坐标)
+	Annotation          string       // 组码 340，关联注释对象 (MTEXT、TOLERANCE 或 INSERT) 的句柄
+	Normal              core.Point   // 组码 210/220/230，法向，默认 (0, 0, 1)
+	HorizontalDirection core.Point   // 组码 211/221/231，水平方向，默认 (1, 0, 0)
+	BlockOffset         core.Point   // 组码 212/222/232，块参照插入点的偏移
+	AnnotationOffset    core.Point   // 组码 213/223/233，注释位置相对最后一个顶点的偏移
+}
+
+func init() {
+	Register("LEADER", func() Entity {
+		return &Leader{
+			BaseEntity:          BaseEntity{TypeName: "LEADER"},
+			Arrowhead:           true,
+			AnnotationType:      LeaderAnnotationNone,
+			Normal:              core.ZAxis,
+			HorizontalDirection: core.Point{X: 1},
+		}
+	})
+}
+
+// NewLeader 创建一条带箭头、没有注释的引线，第一个顶点为箭头位置
+func NewLeader(layer string, vertices ...core.Point) *Leader {
+	l := CreateEntity("LEADER").(*Leader)
+	l.LayerName, l.Vertices = layer, vertices
+	return l
+}
+
+func (l *Leader) Parse(s *core.Scanner) error {
+	for _, t := range s.ReadEntity() {
+		switch t.Code {
+		case 3:
+			l.StyleName = t.Value
+		case 71:
+			l.Arrowhead = t.AsInt() == 1
+		case 72:
+			l.PathType = t.AsInt()
+		case 73:
+			l.AnnotationType = t.AsInt()
+		case 74:
+			l.HooklineDirection = t.AsInt()
+		case 75:
+			l.Hookline = t.AsInt() == 1
+		case 40:
+			l.TextHeight = t.AsFloat()
+		case 41:
+			l.TextWidth = t.AsFloat()
+		case 76:
+			// 顶点数量由 Vertices 决定
+		case 10:
+			l.Vertices = append(l.Vertices, core.Point{X: t.AsFloat()})
+		case 20, 30:
+			if n := len(l.Vertices); n > 0 {
+				setCoord(&l.Vertices[n-1], t)
+			}
+		case 340:
+			l.Annotation = t.Value
+		case 210:
+			l.Normal.X = t.AsFloat()
+		case 220:
+			l.Normal.Y = t.AsFloat()
+		case 230:
+			l.Normal.Z = t.AsFloat()
+		case 211:
+			l.HorizontalDirection.X = t.AsFloat()
+		case 221:
+			l.HorizontalDirection.Y = t.AsFloat()
+		case 231:
+			l.HorizontalDirection.Z = t.AsFloat()
+		case 212:
+			l.BlockOffset.X = t.AsFloat()
+		case 222:
+			l.BlockOffset.Y = t.AsFloat()
+		case 232:
+			l.BlockOffset.Z = t.AsFloat()
+		case 213:
+			l.AnnotationOffset.X = t.AsFloat()
+		case 223:
+			l.AnnotationOffset.Y = t.AsFloat()
+		case 233:
+			l.AnnotationOffset.Z = t.AsFloat()
+		default:
+			l.ParseTag(t)
+		}
+	}
+	return s.Err()
+}
+
+func (l *Leader) Write(w *core.Writer) error {
+	l.WriteBase(w, "AcDbLeader")
+	style := l.StyleName
+	if style == "" {
+		style = "STANDARD"
+	}
+	w.WriteString(3, style)
+	w.WriteInt(71, flag(l.Arrowhead))
+	w.WriteInt(72, l.PathType)
+	w.WriteInt(73, l.AnnotationType)
+	w.WriteInt(74, l.HooklineDirection)
+	w.WriteInt(75, flag(l.Hookline))
+	w.WriteFloat(40, l.TextHeight)
+	w.WriteFloat(41, l.TextWidth)
+	w.WriteInt(76, len(l.Vertices))
+	for _, v := range l.Vertices {
+		w.WritePoint(10, v)
+	}
+	if l.Annotation != "" {
+		w.WriteString(340, l.Annotation)
+	}
+	writeExtrusion(w, l.Normal)
+	if l.HorizontalDirection != (core.Point{X: 1}) && l.HorizontalDirection != (core.Point{}) {
+		w.WritePoint(211, l.HorizontalDirection)
+	}
+	if l.BlockOffset != (core.Point{}) {
+		w.WritePoint(212, l.BlockOffset)
+	}
+	if l.AnnotationOffset != (core.Point{}) {
+		w.WritePoint(213, l.AnnotationOffset)
+	}
+	l.WriteExtra(w)
+	return w.Err()
+}
+
+// ArrowTip 返回箭头位置 (第一个顶点)，没有顶点时 ok 为 false
+func (l *Leader) ArrowTip() (tip core.Point, ok bool) {
+	if len(l.Vertices) == 0 {
+		return
+	}
+	return l.Vertices[0], true
+}
+
+// Transformed 返回变换后的引线
+func (l *Leader) Transformed(m core.Matrix) Entity {
+	c := *l
+	c.Vertices = make([]core.Point, len(l.Vertices))
+	for i, v := range l.Vertices {
+		c.Vertices[i] = m.Apply(v)
+	}
+	if l.Normal != (core.Point{}) {
+		c.Normal = m.ApplyVector(l.Normal).Normalize()
+	}
+	if l.HorizontalDirection != (core.Point{}) {
+		c.HorizontalDirection = m.ApplyVector(l.HorizontalDirection).Normalize()
+	}
+	c.BlockOffset = m.ApplyVector(l.BlockOffset)
+	c.AnnotationOffset = m.ApplyVector(l.AnnotationOffset)
+	c.TextHeight = l.TextHeight * scaleOf(m)
+	c.TextWidth = l.TextWidth * scaleOf(m)
+	return &c
+}
+
+// Empty 是否没有顶点
+func (l *Leader) Empty() bool {
+	return len(l.Vertices) == 0
+}
+
+// BBox 返回顶点的包围盒，不含注释对象
+func (l *Leader) BBox() core.BBox {
+	return pointsBBox(l.Vertices)
+}
+
+// pointsBBox 返回点集的包围盒，没有点时为零值
+func pointsBBox(points []core.Point) core.BBox {
+	if len(points) == 0 {
+		return core.BBox{}
+	}
+	box := core.BBox{Min: points[0], Max: points[0]}
+	for _, p := range points[1:] {
+		box = box.Extend(p)
+	}
+	return box
+}
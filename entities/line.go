@@ -15,26 +15,46 @@ func init() {
 	Register("LINE", func() Entity { return &Line{BaseEntity: BaseEntity{TypeName: "LINE"}} })
 }
 
+// NewLine 创建一条直线
+func NewLine(layer string, start, end core.Point) *Line {
+	return &Line{BaseEntity: BaseEntity{TypeName: "LINE", LayerName: layer}, Start: start, End: end}
+}
+
 func (l *Line) Parse(s *core.Scanner) error {
-	for {
-		t := s.LastTag
+	for _, t := range s.ReadEntity() {
 		switch t.Code {
-		case 8:
-			l.LayerName = t.AsString()
 		case 10:
 			l.Start.X = t.AsFloat()
 		case 20:
 			l.Start.Y = t.AsFloat()
+		case 30:
+			l.Start.Z = t.AsFloat()
 		case 11:
 			l.End.X = t.AsFloat()
 		case 21:
 			l.End.Y = t.AsFloat()
-		}
-		if !s.Next() || s.LastTag.Code == 0 {
-			break
+		case 31:
+			l.End.Z = t.AsFloat()
+		default:
+			l.ParseTag(t)
 		}
 	}
-	return nil
+	return s.Err()
+}
+
+func (l *Line) Write(w *core.Writer) error {
+	l.WriteBase(w, "AcDbLine")
+	w.WritePoint(10, l.Start)
+	w.WritePoint(11, l.End)
+	l.WriteExtra(w)
+	return w.Err()
+}
+
+// Transformed 返回变换后的直线
+func (l *Line) Transformed(m core.Matrix) Entity {
+	c := *l
+	c.Start, c.End = m.Apply(l.Start), m.Apply(l.End)
+	return &c
 }
 
 func (l *Line) BBox() core.BBox {
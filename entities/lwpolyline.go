@@ -1,49 +1,200 @@
 package entities
 
 import (
-	"math"
+	"iter"
 
 	"github.com/zooyer/dxf/core"
 )
 
 type LWPolyline struct {
 	BaseEntity
-	Vertices []core.Point
+	Vertices  []Vertex   // 顶点，坐标在实体坐标系 (OCS) 中，Z 由 Elevation 决定
+	Flags     int        // 组码 70，1 表示闭合，128 表示线型图案连续生成
+	Width     float64    // 组码 43，全局宽度，顶点未设置宽度时使用
+	Elevation float64    // 组码 38，标高
+	Thickness float64    // 组码 39，厚度
+	Extrusion core.Point // 组码 210/220/230，拉伸方向，默认 (0, 0, 1)
+
+	ids []string // 组码 91，顶点编号，与 Vertices 一一对应时原样写出
 }
 
 func init() {
-	Register("LWPOLYLINE", func() Entity { return &LWPolyline{BaseEntity: BaseEntity{TypeName: "LWPOLYLINE"}} })
+	Register("LWPOLYLINE", func() Entity {
+		return &LWPolyline{BaseEntity: BaseEntity{TypeName: "LWPOLYLINE"}, Extrusion: core.ZAxis}
+	})
+}
+
+// NewLWPolyline 创建一条由直线段组成的多段线
+func NewLWPolyline(layer string, points ...core.Point) *LWPolyline {
+	l := &LWPolyline{BaseEntity: BaseEntity{TypeName: "LWPOLYLINE", LayerName: layer}, Extrusion: core.ZAxis}
+	for _, p := range points {
+		l.Vertices = append(l.Vertices, Vertex{Point: core.Point{X: p.X, Y: p.Y}})
+	}
+	return l
+}
+
+// NewRectangle 创建矩形多段线，对应 CAD 中的 RECTANG 命令
+func NewRectangle(layer string, box core.BBox) *LWPolyline {
+	return NewLWPolyline(layer,
+		core.Point{X: box.Min.X, Y: box.Min.Y},
+		core.Point{X: box.Max.X, Y: box.Min.Y},
+		core.Point{X: box.Max.X, Y: box.Max.Y},
+		core.Point{X: box.Min.X, Y: box.Max.Y},
+		core.Point{X: box.Min.X, Y: box.Min.Y},
+	)
 }
 
 func (l *LWPolyline) Parse(s *core.Scanner) error {
-	var x float64
-	for {
-		t := s.LastTag
+	// last 返回当前顶点，40/41/42/91 跟随在所属顶点的 10/20 之后
+	last := func() *Vertex {
+		if n := len(l.Vertices); n > 0 {
+			return &l.Vertices[n-1]
+		}
+		return nil
+	}
+
+	for _, t := range s.ReadEntity() {
 		switch t.Code {
-		case 8:
-			l.LayerName = t.AsString()
 		case 10:
-			x = t.AsFloat()
+			l.Vertices = append(l.Vertices, Vertex{Point: core.Point{X: t.AsFloat()}})
 		case 20:
-			l.Vertices = append(l.Vertices, core.Point{X: x, Y: t.AsFloat()})
+			if v := last(); v != nil {
+				v.Point.Y = t.AsFloat()
+			}
+		case 40:
+			if v := last(); v != nil {
+				v.StartWidth = t.AsFloat()
+			}
+		case 41:
+			if v := last(); v != nil {
+				v.EndWidth = t.AsFloat()
+			}
+		case 42:
+			if v := last(); v != nil {
+				v.Bulge = t.AsFloat()
+			}
+		case 91:
+			// 编号可能只出现在部分顶点上，按顶点位置对齐
+			for len(l.ids) < len(l.Vertices) {
+				l.ids = append(l.ids, "")
+			}
+			if n := len(l.ids); n > 0 {
+				l.ids[n-1] = t.Value
+			}
+		case 70:
+			l.Flags = t.AsInt()
+		case 90:
+			// 顶点数量由 Vertices 决定
+		case 43:
+			l.Width = t.AsFloat()
+		case 38:
+			l.Elevation = t.AsFloat()
+		case 39:
+			l.Thickness = t.AsFloat()
+		case 210:
+			l.Extrusion.X = t.AsFloat()
+		case 220:
+			l.Extrusion.Y = t.AsFloat()
+		case 230:
+			l.Extrusion.Z = t.AsFloat()
+		default:
+			l.ParseTag(t)
 		}
-		if !s.Next() || s.LastTag.Code == 0 {
-			break
+	}
+	return s.Err()
+}
+
+func (l *LWPolyline) Write(w *core.Writer) error {
+	l.WriteBase(w, "AcDbPolyline")
+	w.WriteInt(90, len(l.Vertices))
+	w.WriteInt(70, l.Flags)
+	if l.Width != 0 {
+		w.WriteFloat(43, l.Width)
+	}
+	if l.Elevation != 0 {
+		w.WriteFloat(38, l.Elevation)
+	}
+	if l.Thickness != 0 {
+		w.WriteFloat(39, l.Thickness)
+	}
+	for i, v := range l.Vertices {
+		w.WritePoint2D(10, v.Point)
+		if v.StartWidth != 0 || v.EndWidth != 0 {
+			w.WriteFloat(40, v.StartWidth)
+			w.WriteFloat(41, v.EndWidth)
+		}
+		if v.Bulge != 0 {
+			w.WriteFloat(42, v.Bulge)
+		}
+		if len(l.ids) == len(l.Vertices) && l.ids[i] != "" {
+			w.WriteString(91, l.ids[i])
 		}
 	}
-	return nil
+	writeExtrusion(w, l.Extrusion)
+	l.WriteExtra(w)
+	return w.Err()
 }
 
-func (l *LWPolyline) BBox() core.BBox {
-	if len(l.Vertices) == 0 {
-		return core.BBox{}
+// Closed 是否闭合
+func (l *LWPolyline) Closed() bool {
+	return l.Flags&PolylineClosed != 0
+}
+
+// Points 返回带标高的顶点，Z 为 Elevation
+func (l *LWPolyline) Points() []Vertex {
+	points := make([]Vertex, len(l.Vertices))
+	for i, v := range l.Vertices {
+		points[i] = v
+		points[i].Point.Z = l.Elevation
 	}
-	miX, miY, maX, maY := l.Vertices[0].X, l.Vertices[0].Y, l.Vertices[0].X, l.Vertices[0].Y
-	for _, v := range l.Vertices {
-		miX = math.Min(miX, v.X)
-		miY = math.Min(miY, v.Y)
-		maX = math.Max(maX, v.X)
-		maY = math.Max(maY, v.Y)
+	return points
+}
+
+// Segments 依次返回各段直线或圆弧 (OCS 坐标)，闭合时包含回到起点的一段
+func (l *LWPolyline) Segments() iter.Seq[Segment] {
+	return segments(l.Points(), l.Closed(), [2]float64{l.Width, l.Width})
+}
+
+// Length 返回总长度，圆弧段按弧长计算
+func (l *LWPolyline) Length() float64 {
+	return polylineLength(l.Vertices, l.Closed())
+}
+
+// Area 返回围成的面积，未闭合时按首尾相连计算，圆弧段按实际圆弧计算
+func (l *LWPolyline) Area() float64 {
+	return polylineArea(l.Vertices)
+}
+
+// Transformed 返回变换后的多段线，镜像时凸度取反
+func (l *LWPolyline) Transformed(m core.Matrix) Entity {
+	c := *l
+	m, c.Extrusion = ocsTransform(m, l.Extrusion)
+	c.Elevation = m.Apply(core.Point{Z: l.Elevation}).Z
+	scale := scaleOf(m)
+	mirror := m.Determinant() < 0
+
+	c.Width = l.Width * scale
+	c.Vertices = make([]Vertex, len(l.Vertices))
+	for i, v := range l.Points() {
+		v.Point = m.Apply(v.Point)
+		v.Point.Z = 0
+		v.StartWidth *= scale
+		v.EndWidth *= scale
+		if mirror {
+			v.Bulge = -v.Bulge
+		}
+		c.Vertices[i] = v
 	}
-	return core.BBox{Min: core.Point{X: miX, Y: miY}, Max: core.Point{X: maX, Y: maY}}
+
+	return &c
+}
+
+// Empty 是否没有顶点
+func (l *LWPolyline) Empty() bool {
+	return len(l.Vertices) == 0
+}
+
+// BBox 返回世界坐标下的包围盒，圆弧段按实际圆弧计算
+func (l *LWPolyline) BBox() core.BBox {
+	return polylineBBox(l.Points(), l.Closed(), core.ArbitraryAxis(l.Extrusion))
 }
@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/zooyer/dxf/core"
+)
+
+func TestLWPolyline_Parse(t *testing.T) {
+	// 上边为半圆拱的闭合窗框：(0,0) → (2,0) → (2,1) ⌒ (0,1)
+	data := "0\nLWPOLYLINE\n5\n20\n8\nWIN\n90\n4\n70\n1\n43\n0.5\n38\n3\n" +
+		"10\n0\n20\n0\n10\n2\n20\n0\n10\n2\n20\n1\n42\n1\n10\n0\n20\n1\n40\n1\n41\n2\n0\nEOF\n"
+
+	ent, _ := parseEntity(t, data)
+	l := ent.(*LWPolyline)
+	if len(l.Vertices) != 4 || !l.Closed() || l.Elevation != 3 || l.Width != 0.5 {
+		t.Fatalf("多段线解析不正确: %+v", l)
+	}
+	if v := l.Vertices[2]; v.Bulge != 1 || v.X != 2 || v.Y != 1 {
+		t.Errorf("凸度不正确: %+v", v)
+	}
+	if v := l.Vertices[3]; v.StartWidth != 1 || v.EndWidth != 2 {
+		t.Errorf("宽度不正确: %+v", v)
+	}
+
+	var arcs int
+	for s := range l.Segments() {
+		if s.IsArc() {
+			arcs++
+			if !nearPoint(s.Center(), core.Point{X: 1, Y: 1, Z: 3}) || math.Abs(s.Radius()-1) > 1e-9 {
+				t.Errorf("圆弧段不正确: center=%+v r=%v", s.Center(), s.Radius())
+			}
+		}
+	}
+	if arcs != 1 {
+		t.Errorf("期望 1 段圆弧, 得到 %d", arcs)
+	}
+
+	// 包围盒包含拱顶
+	if box := l.BBox(); !nearPoint(box.Max, core.Point{X: 2, Y: 2, Z: 3}) || !nearPoint(box.Min, core.Point{Z: 3}) {
+		t.Errorf("包围盒不正确: %+v", box)
+	}
+	if length := l.Length(); math.Abs(length-(4+math.Pi)) > 1e-9 {
+		t.Errorf("长度不正确: %v", length)
+	}
+	if area := l.Area(); math.Abs(area-(2+math.Pi/2)) > 1e-9 {
+		t.Errorf("面积不正确: %v", area)
+	}
+
+	var buf bytes.Buffer
+	w := core.NewWriter(&buf)
+	if err := l.Write(w); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Flush()
+	again, _ := parseEntity(t, buf.String())
+	if q := again.(*LWPolyline); q.Vertices[2].Bulge != 1 || q.Vertices[3].EndWidth != 2 || q.Elevation != 3 || q.Flags != 1 {
+		t.Errorf("写出后重新解析不正确: %s", buf.String())
+	}
+}
+
+func TestLWPolyline_Transformed(t *testing.T) {
+	l := NewLWPolyline("0", core.Point{}, core.Point{X: 2})
+	l.Vertices[0].Bulge = 1
+
+	// 沿 Y 轴镜像后圆弧仍在 Y 负方向一侧：(0,0) → (-2,0)，圆弧经过 (-1,-1)
+	c := l.Transformed(core.Scaling(core.Point{X: -1, Y: 1, Z: 1})).(*LWPolyline)
+	if c.Vertices[0].Bulge != -1 {
+		t.Fatalf("镜像后凸度应取反: %+v", c.Vertices[0])
+	}
+	if box := c.BBox(); !nearPoint(box.Min, core.Point{X: -2, Y: -1}) || !nearPoint(box.Max, core.Point{}) {
+		t.Errorf("镜像后的包围盒不正确: %+v", box)
+	}
+}
+
+func TestLWPolyline_Empty(t *testing.T) {
+	l := NewLWPolyline("0")
+	if !IsEmpty(l) || l.BBox() != (core.BBox{}) {
+		t.Errorf("没有顶点的多段线应为空且包围盒为零值: %+v", l.BBox())
+	}
+	if IsEmpty(NewLWPolyline("0", core.Point{})) || IsEmpty(NewLine("0", core.Point{}, core.Point{})) {
+		t.Error("有几何图形的实体不应为空")
+	}
+}
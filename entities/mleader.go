@@ -0,0 +1,321 @@
+package entities
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/zooyer/dxf/core"
+)
+
+// 多重引线的内容类型 (组码 172)
+const (
+	MLeaderContentNone      = 0 // 无内容
+	MLeaderContentBlock     = 1 // 块
+	MLeaderContentMText     = 2 // 多行文字
+	MLeaderContentTolerance = 3 // 公差
+)
+
+// MLeaderLine 多重引线中的一条引线，第一个顶点为箭头位置
+type MLeaderLine struct {
+	Vertices []core.Point // 组码 10/20/30，顶点 (世界坐标)
+}
+
+// MLeaderBranch 多重引线的一个分支 (LEADER{ ... })，多条引线汇聚到同一个连接点
+type MLeaderBranch struct {
+	Landing      core.Point    // 组码 10/20/30，连接点 (最后一个引线点)
+	Dogleg       core.Point    // 组码 11/21/31，基线方向
+	DoglegLength float64       // 组码 40，基线长度
+	Lines        []MLeaderLine // 引线
+}
+
+// MLeader 多重引线 MULTILEADER
+// 组码结构复杂，写出时原样输出读取到的组码，字段只用于查询
+type MLeader struct {
+	BaseEntity
+	ContentType   int             // 组码 172，内容类型
+	BasePoint     core.Point      // 组码 10/20/30，内容基点
+	Text          string          // 组码 304，多行文字内容 (含格式代码)
+	TextLocation  core.Point      // 组码 12/22/32，文字位置
+	TextDirection core.Point      // 组码 13/23/33，文字方向
+	TextHeight    float64         // 组码 41，文字高度
+	TextWidth     float64         // 组码 43，文字宽度
+	TextAttach    int             // 组码 171，文字附着点
+	Block         string          // 组码 341，块内容的块记录句柄
+	BlockLocation core.Point      // 组码 15/25/35，块内容位置
+	Branches      []MLeaderBranch // 引线分支
+
+	data []core.Tag // AcDbMLeader 子类的全部组码
+}
+
+func init() {
+	for _, name := range []string{"MULTILEADER", "MLEADER"} {
+		Register(name, func() Entity {
+			return &MLeader{BaseEntity: BaseEntity{TypeName: name}}
+		})
+	}
+}
+
+// 多重引线组码所在的区段
+const (
+	mleaderTop     = iota // 子类顶层：样式与覆盖属性
+	mleaderContext        // CONTEXT_DATA{ ... }：内容与布局
+	mleaderBranch         // LEADER{ ... }
+	mleaderLine           // LEADER_LINE{ ... }
+)
+
+func (m *MLeader) Parse(s *core.Scanner) error {
+	tags := s.ReadEntity()
+
+	// 子类标记之前为实体公共组码
+	i := 0
+	for ; i < len(tags); i++ {
+		if tags[i].Code == 100 && tags[i].Value == "AcDbMLeader" {
+			i++
+			break
+		}
+		m.ParseTag(tags[i])
+	}
+	m.data = tags[i:]
+	m.parseData()
+
+	return s.Err()
+}
+
+// parseData 从子类组码中解析字段
+func (m *MLeader) parseData() {
+	m.walk(func(section, branch, line, i int) {
+		t := m.data[i]
+		for branch >= 0 && len(m.Branches) <= branch {
+			m.Branches = append(m.Branches, MLeaderBranch{})
+		}
+		for line >= 0 && len(m.Branches[branch].Lines) <= line {
+			m.Branches[branch].Lines = append(m.Branches[branch].Lines, MLeaderLine{})
+		}
+
+		switch section {
+		case mleaderTop:
+			if t.Code == 172 {
+				m.ContentType = t.AsInt()
+			}
+		case mleaderContext:
+			m.parseContext(t)
+		case mleaderBranch:
+			m.Branches[branch].parseTag(t)
+		case mleaderLine:
+			m.Branches[branch].Lines[line].parseTag(t)
+		}
+	})
+}
+
+// walk 依次访问子类组码 (按序号 i) 及其所在的区段，branch、line 为所在分支与引线的序号，不在其中时为 -1
+// 区段的开始与结束标记也会被访问，开始标记属于新的区段，结束标记属于外层区段
+func (m *MLeader) walk(visit func(section, branch, line, i int)) {
+	section, branch, line := mleaderTop, -1, -1
+	for i, t := range m.data {
+		switch {
+		case t.Code == 300 && strings.HasPrefix(t.Value, "CONTEXT_DATA{"):
+			section = mleaderContext
+		case t.Code == 302 && strings.HasPrefix(t.Value, "LEADER{"):
+			section, branch, line = mleaderBranch, branch+1, -1
+		case t.Code == 304 && strings.HasPrefix(t.Value, "LEADER_LINE{") && branch >= 0:
+			section, line = mleaderLine, line+1
+		case t.Code == 305 && section == mleaderLine:
+			section = mleaderBranch
+		case t.Code == 303 && section == mleaderBranch:
+			section = mleaderContext
+		case t.Code == 301 && section == mleaderContext:
+			section = mleaderTop
+		}
+
+		switch section {
+		case mleaderTop, mleaderContext:
+			visit(section, -1, -1, i)
+		case mleaderBranch:
+			visit(section, branch, -1, i)
+		default:
+			visit(section, branch, line, i)
+		}
+	}
+}
+
+// parseContext 解析 CONTEXT_DATA 中的内容组码
+func (m *MLeader) parseContext(t core.Tag) {
+	switch t.Code {
+	case 304:
+		m.Text = t.Value
+	case 10:
+		m.BasePoint.X = t.AsFloat()
+	case 20:
+		m.BasePoint.Y = t.AsFloat()
+	case 30:
+		m.BasePoint.Z = t.AsFloat()
+	case 12:
+		m.TextLocation.X = t.AsFloat()
+	case 22:
+		m.TextLocation.Y = t.AsFloat()
+	case 32:
+		m.TextLocation.Z = t.AsFloat()
+	case 13:
+		m.TextDirection.X = t.AsFloat()
+	case 23:
+		m.TextDirection.Y = t.AsFloat()
+	case 33:
+		m.TextDirection.Z = t.AsFloat()
+	case 41:
+		m.TextHeight = t.AsFloat()
+	case 43:
+		m.TextWidth = t.AsFloat()
+	case 171:
+		m.TextAttach = t.AsInt()
+	case 341:
+		m.Block = t.Value
+	case 15:
+		m.BlockLocation.X = t.AsFloat()
+	case 25:
+		m.BlockLocation.Y = t.AsFloat()
+	case 35:
+		m.BlockLocation.Z = t.AsFloat()
+	}
+}
+
+func (b *MLeaderBranch) parseTag(t core.Tag) {
+	switch t.Code {
+	case 10:
+		b.Landing.X = t.AsFloat()
+	case 20:
+		b.Landing.Y = t.AsFloat()
+	case 30:
+		b.Landing.Z = t.AsFloat()
+	case 11:
+		b.Dogleg.X = t.AsFloat()
+	case 21:
+		b.Dogleg.Y = t.AsFloat()
+	case 31:
+		b.Dogleg.Z = t.AsFloat()
+	case 40:
+		b.DoglegLength = t.AsFloat()
+	}
+}
+
+func (l *MLeaderLine) parseTag(t core.Tag) {
+	switch t.Code {
+	case 10:
+		l.Vertices = append(l.Vertices, core.Point{X: t.AsFloat()})
+	case 20, 30:
+		if n := len(l.Vertices); n > 0 {
+			setCoord(&l.Vertices[n-1], t)
+		}
+	}
+}
+
+func (m *MLeader) Write(w *core.Writer) error {
+	m.WriteBase(w, "AcDbMLeader")
+	writeTags(w, m.data)
+	m.WriteExtra(w)
+	return w.Err()
+}
+
+// 各区段中需要变换的组码：点、方向向量与随缩放变化的长度
+var mleaderTransforms = map[int]struct{ points, vectors, lengths []int }{
+	mleaderContext: {[]int{10, 12, 15, 110}, []int{11, 13, 111, 112}, []int{40, 41, 43, 140, 145}},
+	mleaderBranch:  {[]int{10, 12, 13}, []int{11}, []int{40}},
+	mleaderLine:    {[]int{10, 11, 12}, nil, nil},
+}
+
+// Transformed 返回变换后的多重引线：变换引线顶点 (含箭头位置)、连接点、内容基点与文字位置等组码后重新解析
+func (m *MLeader) Transformed(t core.Matrix) Entity {
+	c := &MLeader{BaseEntity: m.BaseEntity, data: slices.Clone(m.data)}
+	scale := scaleOf(t)
+
+	c.walk(func(section, _, _, i int) {
+		codes, ok := mleaderTransforms[section]
+		if !ok {
+			return
+		}
+		switch code := c.data[i].Code; {
+		case slices.Contains(codes.lengths, code):
+			c.data[i].Value = core.FormatFloat(c.data[i].AsFloat() * scale)
+		case slices.Contains(codes.points, code):
+			c.transformPoint(i, t.Apply)
+		case slices.Contains(codes.vectors, code):
+			c.transformPoint(i, func(v core.Point) core.Point { return t.ApplyVector(v).Normalize() })
+		}
+	})
+
+	c.parseData()
+	return c
+}
+
+// transformPoint 变换从第 i 个组码开始的连续三个坐标组码 (如 10、20、30)，坐标不连续时不变换
+func (m *MLeader) transformPoint(i int, transform func(core.Point) core.Point) {
+	if i+2 >= len(m.data) {
+		return
+	}
+	x, y, z := &m.data[i], &m.data[i+1], &m.data[i+2]
+	if y.Code != x.Code+10 || z.Code != x.Code+20 {
+		return
+	}
+	p := transform(core.Point{X: x.AsFloat(), Y: y.AsFloat(), Z: z.AsFloat()})
+	x.Value, y.Value, z.Value = core.FormatFloat(p.X), core.FormatFloat(p.Y), core.FormatFloat(p.Z)
+}
+
+// ArrowTips 返回所有引线的箭头位置
+func (m *MLeader) ArrowTips() []core.Point {
+	var tips []core.Point
+	for _, b := range m.Branches {
+		for _, l := range b.Lines {
+			if len(l.Vertices) > 0 {
+				tips = append(tips, l.Vertices[0])
+			}
+		}
+	}
+	return tips
+}
+
+// ArrowTip 返回第一条引线的箭头位置，没有引线时 ok 为 false
+func (m *MLeader) ArrowTip() (tip core.Point, ok bool) {
+	if tips := m.ArrowTips(); len(tips) > 0 {
+		return tips[0], true
+	}
+	return
+}
+
+// MText 返回多行文字内容，内容不是多行文字时返回 nil
+func (m *MLeader) MText() *MText {
+	if m.ContentType != MLeaderContentMText && m.Text == "" {
+		return nil
+	}
+	t := NewMText(m.LayerName, m.TextLocation, m.TextHeight, m.Text)
+	t.Width = m.TextWidth
+	t.XAxis = m.TextDirection
+	if m.TextAttach != 0 {
+		t.Attachment = m.TextAttach
+	}
+	return t
+}
+
+// PlainText 返回去除格式代码后的文字内容
+func (m *MLeader) PlainText() string {
+	if t := m.MText(); t != nil {
+		return t.PlainText()
+	}
+	return ""
+}
+
+// BBox 返回引线、连接点与文字的包围盒
+func (m *MLeader) BBox() core.BBox {
+	var points []core.Point
+	for _, b := range m.Branches {
+		points = append(points, b.Landing)
+		for _, l := range b.Lines {
+			points = append(points, l.Vertices...)
+		}
+	}
+	if t := m.MText(); t != nil {
+		box := t.BBox()
+		points = append(points, box.Min, box.Max)
+	} else if m.ContentType == MLeaderContentBlock {
+		points = append(points, m.BlockLocation)
+	}
+	return pointsBBox(points)
+}
@@ -0,0 +1,240 @@
+package entities
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zooyer/dxf/core"
+)
+
+// 多行文字的附着点 (组码 71)
+const (
+	AttachTopLeft      = 1
+	AttachTopCenter    = 2
+	AttachTopRight     = 3
+	AttachMiddleLeft   = 4
+	AttachMiddleCenter = 5
+	AttachMiddleRight  = 6
+	AttachBottomLeft   = 7
+	AttachBottomCenter = 8
+	AttachBottomRight  = 9
+)
+
+// mtextChunk 组码 3 每段的最大字符数
+const mtextChunk = 250
+
+type MText struct {
+	BaseEntity
+	Text        string     // 组码 3 + 1，文字内容 (含格式代码)
+	Location    core.Point // 组码 10/20/30，插入点 (世界坐标)
+	Height      float64    // 组码 40，文字高度
+	Width       float64    // 组码 41，参照矩形宽度，0 表示不自动换行
+	Attachment  int        // 组码 71，附着点，默认左上
+	Direction   int        // 组码 72，书写方向：1 从左到右，3 从上到下，5 随样式
+	Style       string     // 组码 7，文字样式
+	XAxis       core.Point // 组码 11/21/31，X 轴方向向量 (世界坐标)，优先于 Rotation
+	Rotation    float64    // 组码 50，旋转角度 (弧度)
+	LineSpacing float64    // 组码 44，行距因子，默认 1
+	Extrusion   core.Point // 组码 210/220/230，拉伸方向，默认 (0, 0, 1)
+}
+
+func init() {
+	Register("MTEXT", func() Entity {
+		return &MText{
+			BaseEntity:  BaseEntity{TypeName: "MTEXT"},
+			Attachment:  AttachTopLeft,
+			Direction:   1,
+			LineSpacing: 1,
+			Extrusion:   core.ZAxis,
+		}
+	})
+}
+
+// NewMText 创建一个左上角附着的多行文字
+func NewMText(layer string, location core.Point, height float64, text string) *MText {
+	return &MText{
+		BaseEntity:  BaseEntity{TypeName: "MTEXT", LayerName: layer},
+		Text:        text,
+		Location:    location,
+		Height:      height,
+		Attachment:  AttachTopLeft,
+		Direction:   1,
+		LineSpacing: 1,
+		Extrusion:   core.ZAxis,
+	}
+}
+
+func (m *MText) Parse(s *core.Scanner) error {
+	m.parseTags(s.ReadEntity())
+	return s.Err()
+}
+
+// parseTags 解析多行文字的组码，也用于多行属性中嵌入的多行文字
+func (m *MText) parseTags(tags []core.Tag) {
+	var text strings.Builder
+	for _, t := range tags {
+		switch t.Code {
+		case 1, 3:
+			// 超过 250 个字符的文字分为多段组码 3，最后一段为组码 1
+			text.WriteString(t.Value)
+		case 10:
+			m.Location.X = t.AsFloat()
+		case 20:
+			m.Location.Y = t.AsFloat()
+		case 30:
+			m.Location.Z = t.AsFloat()
+		case 40:
+			m.Height = t.AsFloat()
+		case 41:
+			m.Width = t.AsFloat()
+		case 71:
+			m.Attachment = t.AsInt()
+		case 72:
+			m.Direction = t.AsInt()
+		case 7:
+			m.Style = t.Value
+		case 11:
+			m.XAxis.X = t.AsFloat()
+		case 21:
+			m.XAxis.Y = t.AsFloat()
+		case 31:
+			m.XAxis.Z = t.AsFloat()
+		case 50:
+			m.Rotation = t.AsFloat()
+		case 44:
+			m.LineSpacing = t.AsFloat()
+		case 210:
+			m.Extrusion.X = t.AsFloat()
+		case 220:
+			m.Extrusion.Y = t.AsFloat()
+		case 230:
+			m.Extrusion.Z = t.AsFloat()
+		case 42, 43:
+			// 实际宽度与高度由 CAD 计算，写出时不保留
+		default:
+			m.ParseTag(t)
+		}
+	}
+
+	m.Text = text.String()
+}
+
+func (m *MText) Write(w *core.Writer) error {
+	m.WriteBase(w, "AcDbMText")
+	m.writeData(w)
+	m.WriteExtra(w)
+	return w.Err()
+}
+
+// writeData 写出多行文字的数据组码，不含实体公共组码
+func (m *MText) writeData(w *core.Writer) {
+	w.WritePoint(10, m.Location)
+	w.WriteFloat(40, m.Height)
+	w.WriteFloat(41, m.Width)
+	w.WriteInt(71, m.Attachment)
+	w.WriteInt(72, m.Direction)
+
+	text := m.Text
+	for utf8.RuneCountInString(text) > mtextChunk {
+		i := 0
+		for n := 0; n < mtextChunk; n++ {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			i += size
+		}
+		w.WriteString(3, text[:i])
+		text = text[i:]
+	}
+	w.WriteString(1, text)
+
+	if m.Style != "" {
+		w.WriteString(7, m.Style)
+	}
+	writeExtrusion(w, m.Extrusion)
+	if m.XAxis != (core.Point{}) {
+		w.WritePoint(11, m.XAxis)
+	} else if m.Rotation != 0 {
+		w.WriteFloat(50, m.Rotation)
+	}
+	if m.LineSpacing != 1 && m.LineSpacing != 0 {
+		w.WriteFloat(44, m.LineSpacing)
+	}
+}
+
+// Runs 解析格式代码，返回格式相同的文字段
+func (m *MText) Runs() []MTextRun {
+	return ParseMText(m.Text, m.Height)
+}
+
+// PlainText 返回去掉格式代码后的纯文字，段落之间以 "\n" 分隔
+func (m *MText) PlainText() string {
+	return PlainMText(m.Text)
+}
+
+// Angle 返回文字方向的角度 (度)
+func (m *MText) Angle() float64 {
+	if m.XAxis != (core.Point{}) {
+		x := core.ArbitraryAxis(m.Extrusion)
+		if inv, ok := x.Inverse(); ok {
+			v := inv.ApplyVector(m.XAxis)
+			return math.Atan2(v.Y, v.X) * 180.0 / math.Pi
+		}
+	}
+	return m.Rotation * 180.0 / math.Pi
+}
+
+// BBox 按字符数估算多行文字的包围盒，行高按 5/3 倍字高计算
+func (m *MText) BBox() core.BBox {
+	var (
+		lines  = strings.Split(m.PlainText(), "\n")
+		width  = m.Width
+		height = float64(len(lines)) * m.Height * 5 / 3 * math.Max(m.LineSpacing, 0.25)
+		x, y   float64
+	)
+
+	if width == 0 {
+		for _, line := range lines {
+			width = math.Max(width, textWidth(line, m.Height))
+		}
+	}
+
+	attachment := m.Attachment
+	if attachment < AttachTopLeft || attachment > AttachBottomRight {
+		attachment = AttachTopLeft
+	}
+	switch (attachment - 1) % 3 {
+	case 1:
+		x = -width / 2
+	case 2:
+		x = -width
+	}
+	switch (attachment - 1) / 3 {
+	case 0:
+		y = -height
+	case 1:
+		y = -height / 2
+	}
+
+	t := core.ArbitraryAxis(m.Extrusion)
+	if inv, ok := t.Inverse(); ok {
+		t = t.Multiply(core.Translation(inv.Apply(m.Location)))
+	}
+	t = t.Multiply(core.RotationZ(m.Angle()))
+
+	return t.ApplyBBox(core.BBox{Min: core.Point{X: x, Y: y}, Max: core.Point{X: x + width, Y: y + height}})
+}
+
+// Transformed 返回变换后的多行文字，高度与宽度按比例缩放
+func (m *MText) Transformed(t core.Matrix) Entity {
+	c := *m
+	c.Location = t.Apply(m.Location)
+	c.Extrusion = t.ApplyVector(m.Extrusion.Normalize()).Normalize()
+
+	rad := m.Angle() * math.Pi / 180.0
+	c.XAxis = t.ApplyVector(core.ArbitraryAxis(m.Extrusion).ApplyVector(core.Point{X: math.Cos(rad), Y: math.Sin(rad)})).Normalize()
+	c.Rotation = 0
+
+	scale := scaleOf(t)
+	c.Height, c.Width = m.Height*scale, m.Width*scale
+	return &c
+}
@@ -0,0 +1,199 @@
+package entities
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MTextRun 多行文字中格式相同的一段文字
+type MTextRun struct {
+	Text      string  // 文字内容，段落分隔 \P 转换为 "\n"
+	Font      string  // \f 字体名，空表示沿用文字样式
+	Height    float64 // \H 文字高度，0 表示沿用实体高度
+	Bold      bool    // \f...|b1
+	Italic    bool    // \f...|i1
+	Underline bool    // \L ... \l
+	Overline  bool    // \O ... \o
+	Strike    bool    // \K ... \k
+	Color     int     // \C ACI 颜色号，0 表示沿用实体颜色
+	TrueColor int     // \c 真彩色，0 表示未设置
+
+	Stacked bool   // \S 堆叠文字 (分数、上下标)
+	Upper   string // 堆叠的上部
+	Lower   string // 堆叠的下部
+	Stack   byte   // 堆叠方式：'/' 水平分数线，'#' 斜分数线，'^' 上下标
+}
+
+// ParseMText 解析多行文字的格式代码，height 为实体的文字高度，用于换算 \H 的相对高度 (如 \H1.5x;)
+// 支持 \P、\~、\f、\F、\H、\C、\c、\S、\L、\O、\K、{...} 与 %% 控制码，其余格式代码被忽略
+func ParseMText(text string, height float64) []MTextRun {
+	var (
+		runs  []MTextRun
+		state = MTextRun{}
+		stack []MTextRun
+		buf   strings.Builder
+	)
+
+	flush := func() {
+		if buf.Len() == 0 {
+			return
+		}
+		run := state
+		run.Text = DecodeSpecial(buf.String())
+		runs = append(runs, run)
+		buf.Reset()
+	}
+
+	// param 读取 i 之后到分号为止的参数
+	param := func(i int) (string, int) {
+		end := strings.IndexByte(text[i:], ';')
+		if end < 0 {
+			return text[i:], len(text)
+		}
+		return text[i : i+end], i + end + 1
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '{':
+			flush()
+			stack = append(stack, state)
+			i++
+		case c == '}':
+			flush()
+			if n := len(stack); n > 0 {
+				state, stack = stack[n-1], stack[:n-1]
+			}
+			i++
+		case c == '^' && i+1 < len(text):
+			// 插入符号：^I 制表符、^J 换行、^M 回车、"^ " 表示 ^ 本身
+			switch text[i+1] {
+			case 'I':
+				buf.WriteByte('\t')
+			case 'J':
+				buf.WriteByte('\n')
+			case 'M':
+			case ' ':
+				buf.WriteByte('^')
+			default:
+				buf.WriteByte('^')
+				buf.WriteByte(text[i+1])
+			}
+			i += 2
+		case c == '\\' && i+1 < len(text):
+			code := text[i+1]
+			i += 2
+			switch code {
+			case 'P', 'X':
+				buf.WriteByte('\n')
+			case 'N':
+				// 分栏符
+				buf.WriteByte('\n')
+			case '~':
+				buf.WriteRune('\u00a0') // 不间断空格
+			case '\\', '{', '}':
+				buf.WriteByte(code)
+			case 'L', 'l', 'O', 'o', 'K', 'k':
+				flush()
+				on := code >= 'A' && code <= 'Z'
+				switch code {
+				case 'L', 'l':
+					state.Underline = on
+				case 'O', 'o':
+					state.Overline = on
+				default:
+					state.Strike = on
+				}
+			case 'f', 'F':
+				var value string
+				value, i = param(i)
+				flush()
+				parts := strings.Split(value, "|")
+				state.Font, state.Bold, state.Italic = parts[0], false, false
+				for _, p := range parts[1:] {
+					switch {
+					case strings.HasPrefix(p, "b"):
+						state.Bold = p == "b1"
+					case strings.HasPrefix(p, "i"):
+						state.Italic = p == "i1"
+					}
+				}
+			case 'H':
+				var value string
+				value, i = param(i)
+				flush()
+				if factor, ok := strings.CutSuffix(strings.ToLower(value), "x"); ok {
+					f, _ := strconv.ParseFloat(factor, 64)
+					current := state.Height
+					if current == 0 {
+						current = height
+					}
+					state.Height = current * f
+				} else {
+					state.Height, _ = strconv.ParseFloat(value, 64)
+				}
+			case 'C':
+				var value string
+				value, i = param(i)
+				flush()
+				state.Color, _ = strconv.Atoi(value)
+			case 'c':
+				var value string
+				value, i = param(i)
+				flush()
+				state.TrueColor, _ = strconv.Atoi(value)
+			case 'S':
+				var value string
+				value, i = param(i)
+				flush()
+				runs = append(runs, stacked(state, value))
+			case 'A', 'Q', 'T', 'W', 'p':
+				// 对齐、倾斜、字距、宽度、段落格式：不影响文字内容
+				_, i = param(i)
+			case 'U', 'M':
+				// 未能解码的 \U+XXXX、\M+XXXXX 原样保留
+				buf.WriteByte('\\')
+				buf.WriteByte(code)
+			default:
+				buf.WriteByte(code)
+			}
+		default:
+			buf.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+
+	return runs
+}
+
+// stacked 解析堆叠文字 \Supper^lower; 、\Supper/lower; 、\Supper#lower;
+func stacked(state MTextRun, value string) MTextRun {
+	run := state
+	run.Stacked = true
+	run.Text = value
+
+	value = strings.NewReplacer(`\^`, "\x00", `\/`, "\x01", `\#`, "\x02").Replace(value)
+	restore := strings.NewReplacer("\x00", "^", "\x01", "/", "\x02", "#")
+	if i := strings.IndexAny(value, "^/#"); i >= 0 {
+		run.Upper = DecodeSpecial(restore.Replace(value[:i]))
+		run.Lower = DecodeSpecial(restore.Replace(value[i+1:]))
+		run.Stack = value[i]
+		run.Text = run.Upper + "/" + run.Lower
+		if run.Stack == '^' {
+			run.Text = run.Upper + run.Lower
+		}
+	}
+
+	return run
+}
+
+// PlainMText 返回去掉格式代码后的纯文字，段落之间以 "\n" 分隔，堆叠分数写作 "a/b"
+func PlainMText(text string) string {
+	var b strings.Builder
+	for _, run := range ParseMText(text, 0) {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
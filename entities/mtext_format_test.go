@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"testing"
+)
+
+func TestParseMText(t *testing.T) {
+	text := `{\fSimHei|b1|i0|c134|p2;C1518}\P窗高\H2x;1500{\L%%c20}\~\S1/2;\\end^Itab`
+
+	runs := ParseMText(text, 2.5)
+	if len(runs) != 7 {
+		t.Fatalf("期望 7 段, 得到 %d: %+v", len(runs), runs)
+	}
+
+	if r := runs[0]; r.Text != "C1518" || r.Font != "SimHei" || !r.Bold || r.Italic {
+		t.Errorf("字体段不正确: %+v", r)
+	}
+	if r := runs[1]; r.Text != "\n窗高" || r.Font != "" || r.Bold {
+		t.Errorf("花括号结束后应恢复格式: %+v", r)
+	}
+	if r := runs[2]; r.Text != "1500" || r.Height != 5 {
+		t.Errorf("相对高度不正确: %+v", r)
+	}
+	if r := runs[3]; r.Text != "Ø20" || !r.Underline || r.Height != 5 {
+		t.Errorf("下划线段不正确: %+v", r)
+	}
+	if r := runs[5]; !r.Stacked || r.Upper != "1" || r.Lower != "2" || r.Stack != '/' || r.Text != "1/2" {
+		t.Errorf("堆叠分数不正确: %+v", r)
+	}
+
+	if plain := PlainMText(text); plain != "C1518\n窗高1500Ø20\u00a01/2\\end\ttab" {
+		t.Errorf("纯文字不正确: %q", plain)
+	}
+}
+
+func TestDecodeSpecial(t *testing.T) {
+	if s := DecodeSpecial("%%c100 %%d %%p0.5 %%% %%uA%%u %%176"); s != "Ø100 ° ±0.5 % A °" {
+		t.Errorf("控制码转换不正确: %q", s)
+	}
+}
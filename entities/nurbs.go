@@ -0,0 +1,165 @@
+package entities
+
+import (
+	"math"
+
+	"github.com/zooyer/dxf/core"
+)
+
+// nurbs 非均匀有理 B 样条曲线，SPLINE 与填充的样条边共用
+type nurbs struct {
+	degree  int
+	knots   []float64
+	points  []core.Point
+	weights []float64
+}
+
+// newNURBS 创建样条曲线，缺少节点时使用两端夹紧的均匀节点，权重数量不符时视为非有理
+// 控制点数量不足以构成曲线时返回 nil
+func newNURBS(degree int, knots []float64, points []core.Point, weights []float64) *nurbs {
+	n := len(points)
+	if degree < 1 || n < 2 {
+		return nil
+	}
+	if len(knots) != n+degree+1 || degree >= n {
+		degree = min(degree, n-1)
+		knots = clampedKnots(degree, n)
+	}
+	if len(weights) != n {
+		weights = nil
+	}
+
+	return &nurbs{degree: degree, knots: knots, points: points, weights: weights}
+}
+
+// clampedKnots 返回两端夹紧的均匀节点向量
+func clampedKnots(degree, n int) []float64 {
+	knots := make([]float64, n+degree+1)
+	for i := range knots {
+		switch {
+		case i <= degree:
+			knots[i] = 0
+		case i >= n:
+			knots[i] = 1
+		default:
+			knots[i] = float64(i-degree) / float64(n-degree)
+		}
+	}
+	return knots
+}
+
+// domain 返回参数的定义域
+func (c *nurbs) domain() (start, end float64) {
+	return c.knots[c.degree], c.knots[len(c.points)]
+}
+
+// weight 返回第 i 个控制点的权重
+func (c *nurbs) weight(i int) float64 {
+	if c.weights == nil {
+		return 1
+	}
+	return c.weights[i]
+}
+
+// at 用 de Boor 算法计算参数 t 处的点，t 超出定义域时取端点
+func (c *nurbs) at(t float64) core.Point {
+	p, n := c.degree, len(c.points)
+	start, end := c.domain()
+	t = math.Max(start, math.Min(end, t))
+
+	// 节点区间 k：knots[k] <= t < knots[k+1]，t 在终点时取最后一个非空区间
+	k := p
+	for k < n-1 && c.knots[k+1] <= t {
+		k++
+	}
+
+	// 齐次坐标 (w·P, w)
+	type point struct {
+		p core.Point
+		w float64
+	}
+	d := make([]point, p+1)
+	for j := range d {
+		w := c.weight(j + k - p)
+		d[j] = point{p: c.points[j+k-p].Mul(w), w: w}
+	}
+
+	for r := 1; r <= p; r++ {
+		for j := p; j >= r; j-- {
+			i := j + k - p
+			alpha := 0.0
+			if den := c.knots[i+p-r+1] - c.knots[i]; den != 0 {
+				alpha = (t - c.knots[i]) / den
+			}
+			d[j] = point{
+				p: d[j-1].p.Mul(1 - alpha).Add(d[j].p.Mul(alpha)),
+				w: d[j-1].w*(1-alpha) + d[j].w*alpha,
+			}
+		}
+	}
+
+	if d[p].w == 0 {
+		return d[p].p
+	}
+	return d[p].p.Mul(1 / d[p].w)
+}
+
+// tolerance 返回默认的离散精度：控制多边形包围盒对角线的十万分之一
+func (c *nurbs) tolerance() float64 {
+	box := core.BBox{Min: c.points[0], Max: c.points[0]}
+	for _, p := range c.points[1:] {
+		box = box.Extend(p)
+	}
+	return math.Max(box.Max.Sub(box.Min).Length()*1e-5, 1e-9)
+}
+
+// flatten 将曲线离散为折线 (含起点与终点)，曲线到折线的距离不超过 tolerance，tolerance <= 0 时使用默认精度
+func (c *nurbs) flatten(tolerance float64) []core.Point {
+	if tolerance <= 0 {
+		tolerance = c.tolerance()
+	}
+
+	start, end := c.domain()
+	points := []core.Point{c.at(start)}
+
+	var subdivide func(t0, t1 float64, p0, p1 core.Point, depth int)
+	subdivide = func(t0, t1 float64, p0, p1 core.Point, depth int) {
+		tm := (t0 + t1) / 2
+		pm := c.at(tm)
+		if depth < 16 && distanceToSegment(pm, p0, p1) > tolerance {
+			subdivide(t0, tm, p0, pm, depth+1)
+			subdivide(tm, t1, pm, p1, depth+1)
+			return
+		}
+		points = append(points, p1)
+	}
+
+	// 每个非空节点区间先均分，避免中点恰好落在弦上时漏掉弯曲
+	pieces := max(2*c.degree, 4)
+	for i := c.degree; i < len(c.points); i++ {
+		a, b := math.Max(c.knots[i], start), math.Min(c.knots[i+1], end)
+		if b <= a {
+			continue
+		}
+		prev := c.at(a)
+		for j := 1; j <= pieces; j++ {
+			t0, t1 := a+(b-a)*float64(j-1)/float64(pieces), a+(b-a)*float64(j)/float64(pieces)
+			next := c.at(t1)
+			subdivide(t0, t1, prev, next, 0)
+			prev = next
+		}
+	}
+
+	return points
+}
+
+// distanceToSegment 返回点 p 到线段 ab 的距离
+func distanceToSegment(p, a, b core.Point) float64 {
+	ab := b.Sub(a)
+	l := ab.Dot(ab)
+	if l == 0 {
+		return p.Sub(a).Length()
+	}
+	t := math.Max(0, math.Min(1, p.Sub(a).Dot(ab)/l))
+	return p.Sub(a.Add(ab.Mul(t))).Length()
+}
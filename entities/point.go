@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"github.com/zooyer/dxf/core"
+)
+
+// Point 点，常用作五金件等的定位标记
+type Point struct {
+	BaseEntity
+	Location  core.Point // 组码 10/20/30，位置 (世界坐标)
+	Thickness float64    // 组码 39，厚度
+	Extrusion core.Point // 组码 210/220/230，拉伸方向，默认 (0, 0, 1)
+	Angle     float64    // 组码 50，显示点样式时 X 轴的角度 (度)
+}
+
+func init() {
+	Register("POINT", func() Entity {
+		return &Point{BaseEntity: BaseEntity{TypeName: "POINT"}, Extrusion: core.ZAxis}
+	})
+}
+
+// NewPoint 创建一个点
+func NewPoint(layer string, location core.Point) *Point {
+	return &Point{BaseEntity: BaseEntity{TypeName: "POINT", LayerName: layer}, Location: location, Extrusion: core.ZAxis}
+}
+
+func (p *Point) Parse(s *core.Scanner) error {
+	for _, t := range s.ReadEntity() {
+		switch t.Code {
+		case 10:
+			p.Location.X = t.AsFloat()
+		case 20:
+			p.Location.Y = t.AsFloat()
+		case 30:
+			p.Location.Z = t.AsFloat()
+		case 39:
+			p.Thickness = t.AsFloat()
+		case 50:
+			p.Angle = t.AsFloat()
+		case 210:
+			p.Extrusion.X = t.AsFloat()
+		case 220:
+			p.Extrusion.Y = t.AsFloat()
+		case 230:
+			p.Extrusion.Z = t.AsFloat()
+		default:
+			p.ParseTag(t)
+		}
+	}
+	return s.Err()
+}
+
+func (p *Point) Write(w *core.Writer) error {
+	p.WriteBase(w, "AcDbPoint")
+	w.WritePoint(10, p.Location)
+	if p.Thickness != 0 {
+		w.WriteFloat(39, p.Thickness)
+	}
+	writeExtrusion(w, p.Extrusion)
+	if p.Angle != 0 {
+		w.WriteFloat(50, p.Angle)
+	}
+	p.WriteExtra(w)
+	return w.Err()
+}
+
+// Transformed 返回变换后的点
+func (p *Point) Transformed(m core.Matrix) Entity {
+	c := *p
+	c.Location = m.Apply(p.Location)
+	if p.Extrusion != (core.Point{}) {
+		c.Extrusion = m.ApplyVector(p.Extrusion).Normalize()
+	}
+	return &c
+}
+
+func (p *Point) BBox() core.BBox {
+	return core.BBox{Min: p.Location, Max: p.Location}
+}
@@ -0,0 +1,451 @@
+package entities
+
+import (
+	"iter"
+	"strings"
+
+	"github.com/zooyer/dxf/core"
+)
+
+// 多段线标志 (POLYLINE 组码 70)
+const (
+	PolylineClosed      = 1   // 闭合 (多边形网格为 M 方向闭合)
+	PolylineCurveFit    = 2   // 已曲线拟合
+	PolylineSplineFit   = 4   // 已样条拟合
+	Polyline3D          = 8   // 三维多段线
+	PolylineMesh        = 16  // 三维多边形网格
+	PolylineMeshClosedN = 32  // 多边形网格 N 方向闭合
+	PolylinePolyface    = 64  // 多面网格
+	PolylineLinetypeGen = 128 // 线型图案连续生成
+)
+
+// 顶点标志 (VERTEX 组码 70)
+const (
+	VertexExtra        = 1   // 曲线拟合生成的额外顶点
+	VertexTangent      = 2   // 定义了曲线拟合切线方向
+	VertexSplineFit    = 8   // 样条拟合生成的顶点
+	VertexSplineFrame  = 16  // 样条框架控制点
+	Vertex3D           = 32  // 三维多段线顶点
+	VertexMesh         = 64  // 多边形网格顶点
+	VertexPolyfaceMesh = 128 // 多面网格顶点 (不含 64 时为面记录)
+)
+
+// PolylineVertex POLYLINE 之后的 VERTEX 实体
+type PolylineVertex struct {
+	BaseEntity
+	Vertex
+	Flags   int     // 组码 70，顶点标志
+	Tangent float64 // 组码 50，曲线拟合切线方向 (度)
+	Indices [4]int  // 组码 71~74，面记录的顶点索引 (从 1 开始，负数表示该边不可见)
+}
+
+func init() {
+	Register("POLYLINE", func() Entity {
+		return &Polyline{BaseEntity: BaseEntity{TypeName: "POLYLINE"}, Extrusion: core.ZAxis}
+	})
+	Register("VERTEX", func() Entity {
+		return &PolylineVertex{BaseEntity: BaseEntity{TypeName: "VERTEX"}}
+	})
+}
+
+func (v *PolylineVertex) Parse(s *core.Scanner) error {
+	for _, t := range s.ReadEntity() {
+		switch t.Code {
+		case 10:
+			v.Point.X = t.AsFloat()
+		case 20:
+			v.Point.Y = t.AsFloat()
+		case 30:
+			v.Point.Z = t.AsFloat()
+		case 40:
+			v.StartWidth = t.AsFloat()
+		case 41:
+			v.EndWidth = t.AsFloat()
+		case 42:
+			v.Bulge = t.AsFloat()
+		case 70:
+			v.Flags = t.AsInt()
+		case 50:
+			v.Tangent = t.AsFloat()
+		case 71, 72, 73, 74:
+			v.Indices[t.Code-71] = t.AsInt()
+		default:
+			v.ParseTag(t)
+		}
+	}
+	return s.Err()
+}
+
+// IsFace 是否为多面网格的面记录
+func (v *PolylineVertex) IsFace() bool {
+	return v.Flags&VertexPolyfaceMesh != 0 && v.Flags&VertexMesh == 0
+}
+
+func (v *PolylineVertex) Write(w *core.Writer) error {
+	v.WriteBase(w, "AcDbVertex")
+	switch {
+	case v.IsFace():
+		w.WriteString(100, "AcDbFaceRecord")
+	case v.Flags&VertexPolyfaceMesh != 0:
+		w.WriteString(100, "AcDbPolyFaceMeshVertex")
+	case v.Flags&VertexMesh != 0:
+		w.WriteString(100, "AcDbPolygonMeshVertex")
+	case v.Flags&Vertex3D != 0:
+		w.WriteString(100, "AcDb3dPolylineVertex")
+	default:
+		w.WriteString(100, "AcDb2dVertex")
+	}
+	w.WritePoint(10, v.Point)
+	if v.StartWidth != 0 {
+		w.WriteFloat(40, v.StartWidth)
+	}
+	if v.EndWidth != 0 {
+		w.WriteFloat(41, v.EndWidth)
+	}
+	if v.Bulge != 0 {
+		w.WriteFloat(42, v.Bulge)
+	}
+	w.WriteInt(70, v.Flags)
+	if v.Flags&VertexTangent != 0 {
+		w.WriteFloat(50, v.Tangent)
+	}
+	for i, index := range v.Indices {
+		if index != 0 {
+			w.WriteInt(71+i, index)
+		}
+	}
+	v.WriteExtra(w)
+	return w.Err()
+}
+
+func (v *PolylineVertex) BBox() core.BBox {
+	return core.BBox{Min: v.Point, Max: v.Point}
+}
+
+// Polyline 旧式多段线 POLYLINE ... VERTEX ... SEQEND，包括二维、三维多段线、多边形网格与多面网格
+type Polyline struct {
+	BaseEntity
+	Flags      int        // 组码 70，多段线标志
+	Elevation  float64    // 组码 30，二维多段线的标高
+	Thickness  float64    // 组码 39，厚度
+	StartWidth float64    // 组码 40，默认起点宽度
+	EndWidth   float64    // 组码 41，默认终点宽度
+	MeshM      int        // 组码 71，多边形网格 M 方向顶点数；多面网格为顶点数
+	MeshN      int        // 组码 72，多边形网格 N 方向顶点数；多面网格为面数
+	SmoothM    int        // 组码 73，平滑曲面 M 方向密度
+	SmoothN    int        // 组码 74，平滑曲面 N 方向密度
+	Surface    int        // 组码 75，平滑曲面类型
+	Extrusion  core.Point // 组码 210/220/230，拉伸方向，默认 (0, 0, 1)
+	Vertices   []*PolylineVertex
+	SeqEnd     string // SEQEND 的句柄
+}
+
+// NewPolyline 创建一条二维多段线
+func NewPolyline(layer string, vertices ...Vertex) *Polyline {
+	p := &Polyline{BaseEntity: BaseEntity{TypeName: "POLYLINE", LayerName: layer}, Extrusion: core.ZAxis}
+	for _, v := range vertices {
+		p.Vertices = append(p.Vertices, &PolylineVertex{BaseEntity: BaseEntity{TypeName: "VERTEX", LayerName: layer}, Vertex: v})
+	}
+	return p
+}
+
+func (p *Polyline) Parse(s *core.Scanner) error {
+	for _, t := range s.ReadEntity() {
+		switch t.Code {
+		case 10, 20:
+			// 虚拟点，只有 Z 值 (标高) 有意义
+		case 30:
+			p.Elevation = t.AsFloat()
+		case 66:
+			// 顶点跟随标志，POLYLINE 之后总是有 VERTEX
+		case 70:
+			p.Flags = t.AsInt()
+		case 39:
+			p.Thickness = t.AsFloat()
+		case 40:
+			p.StartWidth = t.AsFloat()
+		case 41:
+			p.EndWidth = t.AsFloat()
+		case 71:
+			p.MeshM = t.AsInt()
+		case 72:
+			p.MeshN = t.AsInt()
+		case 73:
+			p.SmoothM = t.AsInt()
+		case 74:
+			p.SmoothN = t.AsInt()
+		case 75:
+			p.Surface = t.AsInt()
+		case 210:
+			p.Extrusion.X = t.AsFloat()
+		case 220:
+			p.Extrusion.Y = t.AsFloat()
+		case 230:
+			p.Extrusion.Z = t.AsFloat()
+		default:
+			p.ParseTag(t)
+		}
+	}
+
+	// 顶点紧跟在 POLYLINE 之后，以 SEQEND 结束；遇到其他实体说明文件缺少 SEQEND，留给调用方处理
+	for {
+		tag, ok := s.Peek()
+		if !ok || tag.Code != 0 {
+			break
+		}
+
+		switch strings.ToUpper(tag.Value) {
+		case "VERTEX":
+			s.Next()
+			vertex := CreateEntity(tag.Value).(*PolylineVertex)
+			if err := vertex.Parse(s); err != nil {
+				return err
+			}
+			p.Vertices = append(p.Vertices, vertex)
+			continue
+		case "SEQEND":
+			s.Next()
+			for _, t := range s.ReadEntity() {
+				if t.Code == 5 {
+					p.SeqEnd = t.Value
+				}
+			}
+		}
+		break
+	}
+
+	return s.Err()
+}
+
+// subclass 返回多段线与顶点的子类标记
+func (p *Polyline) subclass() string {
+	switch {
+	case p.Flags&PolylinePolyface != 0:
+		return "AcDbPolyFaceMesh"
+	case p.Flags&PolylineMesh != 0:
+		return "AcDbPolygonMesh"
+	case p.Flags&Polyline3D != 0:
+		return "AcDb3dPolyline"
+	}
+	return "AcDb2dPolyline"
+}
+
+func (p *Polyline) Write(w *core.Writer) error {
+	handle := p.WriteBase(w, p.subclass())
+	w.WriteInt(66, 1)
+	w.WritePoint(10, core.Point{Z: p.Elevation})
+	if p.Thickness != 0 {
+		w.WriteFloat(39, p.Thickness)
+	}
+	w.WriteInt(70, p.Flags)
+	if p.StartWidth != 0 {
+		w.WriteFloat(40, p.StartWidth)
+	}
+	if p.EndWidth != 0 {
+		w.WriteFloat(41, p.EndWidth)
+	}
+	if p.MeshM != 0 {
+		w.WriteInt(71, p.MeshM)
+	}
+	if p.MeshN != 0 {
+		w.WriteInt(72, p.MeshN)
+	}
+	if p.SmoothM != 0 {
+		w.WriteInt(73, p.SmoothM)
+	}
+	if p.SmoothN != 0 {
+		w.WriteInt(74, p.SmoothN)
+	}
+	if p.Surface != 0 {
+		w.WriteInt(75, p.Surface)
+	}
+	writeExtrusion(w, p.Extrusion)
+	p.WriteExtra(w)
+
+	// 顶点跟随在 POLYLINE 之后，以 SEQEND 结束
+	// 所属对象、图层与类型标志只写入副本，不修改调用方的顶点
+	for _, vertex := range p.Vertices {
+		v := *vertex
+		v.Owner = handle
+		if v.LayerName == "" {
+			v.LayerName = p.LayerName
+		}
+		switch {
+		case p.Flags&PolylinePolyface != 0:
+			if !v.IsFace() {
+				v.Flags |= VertexMesh | VertexPolyfaceMesh
+			}
+		case p.Flags&PolylineMesh != 0:
+			v.Flags |= VertexMesh
+		case p.Flags&Polyline3D != 0:
+			v.Flags |= Vertex3D
+		}
+		if err := v.Write(w); err != nil {
+			return err
+		}
+	}
+
+	seqEnd := p.SeqEnd
+	if seqEnd == "" {
+		seqEnd = w.Handle()
+	}
+	w.WriteString(0, "SEQEND")
+	w.WriteString(5, seqEnd)
+	w.WriteString(330, handle)
+	w.WriteString(100, "AcDbEntity")
+	w.WriteLayer(p.LayerName)
+
+	return w.Err()
+}
+
+// Closed 是否闭合
+func (p *Polyline) Closed() bool {
+	return p.Flags&PolylineClosed != 0
+}
+
+// Is3D 是否为三维多段线、多边形网格或多面网格，顶点为世界坐标
+func (p *Polyline) Is3D() bool {
+	return p.Flags&(Polyline3D|PolylineMesh|PolylinePolyface) != 0
+}
+
+// Points 返回多段线的顶点，与 LWPolyline 的顶点模型相同
+// 不含样条框架控制点与多面网格的面记录；二维多段线的 Z 为标高
+func (p *Polyline) Points() []Vertex {
+	var points []Vertex
+	for _, v := range p.Vertices {
+		if v.IsFace() || v.Flags&VertexSplineFrame != 0 {
+			continue
+		}
+		vertex := v.Vertex
+		if !p.Is3D() {
+			vertex.Point.Z = p.Elevation
+		}
+		points = append(points, vertex)
+	}
+	return points
+}
+
+// Faces 返回多面网格每个面的顶点 (世界坐标)，不可见边的索引同样解析为顶点
+func (p *Polyline) Faces() [][]core.Point {
+	if p.Flags&PolylinePolyface == 0 {
+		return nil
+	}
+
+	var points []core.Point
+	for _, v := range p.Vertices {
+		if !v.IsFace() {
+			points = append(points, v.Point)
+		}
+	}
+
+	var faces [][]core.Point
+	for _, v := range p.Vertices {
+		if !v.IsFace() {
+			continue
+		}
+		var face []core.Point
+		for _, index := range v.Indices {
+			if index < 0 {
+				index = -index
+			}
+			if index > 0 && index <= len(points) {
+				face = append(face, points[index-1])
+			}
+		}
+		faces = append(faces, face)
+	}
+
+	return faces
+}
+
+// wcs 返回顶点坐标到世界坐标的变换
+func (p *Polyline) wcs() core.Matrix {
+	if p.Is3D() {
+		return core.Identity()
+	}
+	return core.ArbitraryAxis(p.Extrusion)
+}
+
+// isMesh 是否为多边形网格或多面网格
+func (p *Polyline) isMesh() bool {
+	return p.Flags&(PolylineMesh|PolylinePolyface) != 0
+}
+
+// Segments 依次返回各段直线或圆弧，闭合时包含回到起点的一段；网格没有线段
+func (p *Polyline) Segments() iter.Seq[Segment] {
+	if p.isMesh() {
+		return func(yield func(Segment) bool) {}
+	}
+	return segments(p.Points(), p.Closed(), [2]float64{p.StartWidth, p.EndWidth})
+}
+
+// Length 返回总长度，圆弧段按弧长计算；网格为 0
+func (p *Polyline) Length() float64 {
+	if p.isMesh() {
+		return 0
+	}
+	return polylineLength(p.Points(), p.Closed())
+}
+
+// Area 返回二维多段线围成的面积，未闭合时按首尾相连计算；三维多段线与网格为 0
+func (p *Polyline) Area() float64 {
+	if p.Is3D() {
+		return 0
+	}
+	return polylineArea(p.Points())
+}
+
+// Empty 是否没有顶点
+func (p *Polyline) Empty() bool {
+	return len(p.Points()) == 0
+}
+
+// BBox 返回世界坐标下的包围盒，圆弧段按实际圆弧计算
+func (p *Polyline) BBox() core.BBox {
+	points := p.Points()
+	if len(points) == 0 {
+		return core.BBox{}
+	}
+	if p.isMesh() {
+		box := core.BBox{Min: points[0].Point, Max: points[0].Point}
+		for _, v := range points[1:] {
+			box = box.Extend(v.Point)
+		}
+		return box
+	}
+	return polylineBBox(points, p.Closed(), p.wcs())
+}
+
+// Transformed 返回变换后的多段线，镜像时凸度取反
+func (p *Polyline) Transformed(m core.Matrix) Entity {
+	c := *p
+	if !p.Is3D() {
+		m, c.Extrusion = ocsTransform(m, p.Extrusion)
+		c.Elevation = m.Apply(core.Point{Z: p.Elevation}).Z
+	}
+	scale := scaleOf(m)
+	mirror := m.Determinant() < 0
+
+	c.StartWidth, c.EndWidth = p.StartWidth*scale, p.EndWidth*scale
+	c.Vertices = make([]*PolylineVertex, len(p.Vertices))
+	for i, v := range p.Vertices {
+		vertex := *v
+		if !v.IsFace() {
+			point := v.Point
+			if !p.Is3D() {
+				point.Z = p.Elevation
+			}
+			vertex.Point = m.Apply(point)
+			if !p.Is3D() {
+				vertex.Point.Z = 0
+			}
+		}
+		vertex.StartWidth, vertex.EndWidth = v.StartWidth*scale, v.EndWidth*scale
+		if mirror {
+			vertex.Bulge = -v.Bulge
+		}
+		c.Vertices[i] = &vertex
+	}
+
+	return &c
+}
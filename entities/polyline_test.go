@@ -0,0 +1,96 @@
+package entities
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zooyer/dxf/core"
+)
+
+// parseEntity 从 DXF 片段解析第一个实体，返回实体与之后的组码
+func parseEntity(t *testing.T, data string) (Entity, *core.Scanner) {
+	t.Helper()
+	s := core.NewScanner(strings.NewReader(data))
+	if !s.Next() {
+		t.Fatalf("读取失败: %v", s.Err())
+	}
+	ent := CreateEntity(s.LastTag.Value)
+	if err := ent.Parse(s); err != nil {
+		t.Fatal(err)
+	}
+	return ent, s
+}
+
+func TestPolyline_Parse(t *testing.T) {
+	data := "0\nPOLYLINE\n5\n10\n8\nWALL\n66\n1\n10\n0\n20\n0\n30\n2\n70\n1\n" +
+		"0\nVERTEX\n5\n11\n8\nWALL\n10\n0\n20\n0\n42\n1\n70\n0\n" +
+		"0\nVERTEX\n5\n12\n8\nWALL\n10\n2\n20\n0\n70\n0\n" +
+		"0\nSEQEND\n5\n13\n8\nWALL\n" +
+		"0\nLINE\n"
+
+	ent, s := parseEntity(t, data)
+	p := ent.(*Polyline)
+	if len(p.Vertices) != 2 || p.SeqEnd != "13" || !p.Closed() || p.Elevation != 2 {
+		t.Fatalf("多段线解析不正确: %+v", p)
+	}
+	points := p.Points()
+	if points[0].Bulge != 1 || points[1].Point != (core.Point{X: 2, Z: 2}) {
+		t.Errorf("顶点不正确: %+v", points)
+	}
+	if !s.Next() || s.LastTag.Value != "LINE" {
+		t.Errorf("SEQEND 之后应读到 LINE, 得到 %+v", s.LastTag)
+	}
+
+	// 镜像后凸度取反
+	mirrored := p.Transformed(core.Scaling(core.Point{X: -1, Y: 1, Z: 1})).(*Polyline)
+	if v := mirrored.Vertices[1]; v.Bulge != 0 || v.Point.X != -2 || mirrored.Vertices[0].Bulge != -1 {
+		t.Errorf("镜像结果不正确: %+v %+v", mirrored.Vertices[0], mirrored.Vertices[1])
+	}
+
+	var buf bytes.Buffer
+	w := core.NewWriter(&buf)
+	if err := p.Write(w); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Flush()
+	again, _ := parseEntity(t, buf.String())
+	if q := again.(*Polyline); len(q.Vertices) != 2 || q.SeqEnd != "13" || q.Vertices[0].Owner != "10" {
+		t.Errorf("写出后重新解析不正确: %s", buf.String())
+	}
+
+	// 写出时补充的顶点图层与 3D 标志不修改原顶点
+	p3 := NewPolyline("WALL", Vertex{}, Vertex{Point: core.Point{X: 1, Z: 1}})
+	p3.Flags, p3.Vertices[0].LayerName = Polyline3D, ""
+	if err := p3.Write(w); err != nil {
+		t.Fatal(err)
+	}
+	if v := p3.Vertices[0]; v.LayerName != "" || v.Flags != 0 || v.Handle != "" {
+		t.Errorf("写出修改了顶点: %+v", v)
+	}
+}
+
+func TestPolyline_Faces(t *testing.T) {
+	// 两个三角形组成的多面网格，第二个面有一条不可见边
+	data := "0\nPOLYLINE\n66\n1\n70\n64\n71\n4\n72\n2\n" +
+		"0\nVERTEX\n10\n0\n20\n0\n30\n0\n70\n192\n" +
+		"0\nVERTEX\n10\n1\n20\n0\n30\n0\n70\n192\n" +
+		"0\nVERTEX\n10\n1\n20\n1\n30\n1\n70\n192\n" +
+		"0\nVERTEX\n10\n0\n20\n1\n30\n0\n70\n192\n" +
+		"0\nVERTEX\n10\n0\n20\n0\n30\n0\n70\n128\n71\n1\n72\n2\n73\n3\n" +
+		"0\nVERTEX\n10\n0\n20\n0\n30\n0\n70\n128\n71\n1\n72\n-3\n73\n4\n" +
+		"0\nSEQEND\n"
+
+	ent, _ := parseEntity(t, data)
+	p := ent.(*Polyline)
+	if !p.Is3D() || len(p.Points()) != 4 {
+		t.Fatalf("多面网格解析不正确: %+v", p)
+	}
+	faces := p.Faces()
+	if len(faces) != 2 || faces[1][1] != (core.Point{X: 1, Y: 1, Z: 1}) {
+		t.Errorf("面不正确: %+v", faces)
+	}
+	if box := p.BBox(); box.Max != (core.Point{X: 1, Y: 1, Z: 1}) {
+		t.Errorf("包围盒不正确: %+v", box)
+	}
+}
@@ -0,0 +1,156 @@
+package entities
+
+import (
+	"math"
+	"strings"
+
+	"github.com/zooyer/dxf/core"
+)
+
+// RawEntity 保存未注册类型的实体 (如 3DSOLID、IMAGE)，按原始顺序保留所有标签，写出时原样输出
+type RawEntity struct {
+	BaseEntity
+	Tags []core.Tag // 实体的全部标签，不含开头的 (0, 类型名)
+}
+
+// NewRawEntity 创建指定类型的原始实体
+func NewRawEntity(typeName string) *RawEntity {
+	return &RawEntity{BaseEntity: BaseEntity{TypeName: typeName}}
+}
+
+func (r *RawEntity) Parse(s *core.Scanner) error {
+	var inGroup bool
+	r.Tags = s.ReadEntity()
+	for _, t := range r.Tags {
+
+		if inGroup || t.Code == 102 {
+			if t.Code == 102 {
+				inGroup = strings.HasPrefix(t.Value, "{")
+			}
+			continue
+		}
+
+		switch t.Code {
+		case 5, 105:
+			if r.Handle == "" {
+				r.Handle = t.Value
+			}
+		case 8:
+			r.LayerName = t.Value
+		case 330:
+			if r.Owner == "" {
+				r.Owner = t.Value
+			}
+		}
+	}
+	return s.Err()
+}
+
+// Write 原样写出所有标签，句柄、图层与所属对象以 BaseEntity 中的值为准
+func (r *RawEntity) Write(w *core.Writer) error {
+	var (
+		inGroup   bool
+		hasHandle bool
+		hasOwner  bool
+		handle    = r.Handle
+		owner     = w.TakeOwner()
+	)
+	if owner == "" {
+		owner = r.Owner
+	}
+
+	w.WriteString(0, r.TypeName)
+	for _, t := range r.Tags {
+		if t.Code == 5 || t.Code == 105 {
+			hasHandle = true
+			break
+		}
+	}
+	if !hasHandle {
+		if handle == "" {
+			handle = w.Handle()
+		}
+		w.WriteString(5, handle)
+	}
+
+	for _, t := range r.Tags {
+		if inGroup || t.Code == 102 {
+			if t.Code == 102 {
+				inGroup = strings.HasPrefix(t.Value, "{")
+			}
+			w.WriteString(t.Code, t.Value)
+			continue
+		}
+
+		switch {
+		case (t.Code == 5 || t.Code == 105) && handle != "":
+			t.Value = handle
+		case t.Code == 8 && r.LayerName != "":
+			t.Value = r.LayerName
+		case t.Code == 330 && !hasOwner:
+			hasOwner = true
+			if owner != "" {
+				t.Value = owner
+			}
+		}
+		w.WriteString(t.Code, t.Value)
+	}
+
+	return w.Err()
+}
+
+// Value 返回第一个指定组码的值
+func (r *RawEntity) Value(code int) string {
+	for _, t := range r.Tags {
+		if t.Code == code {
+			return t.Value
+		}
+	}
+	return ""
+}
+
+// Empty 是否没有坐标组码
+func (r *RawEntity) Empty() bool {
+	_, ok := r.bbox()
+	return !ok
+}
+
+// BBox 以所有坐标组码 (10~18 与对应的 20~28) 估算包围盒
+func (r *RawEntity) BBox() core.BBox {
+	box, _ := r.bbox()
+	return box
+}
+
+// bbox 以坐标组码估算包围盒，没有坐标时 ok 为 false
+func (r *RawEntity) bbox() (core.BBox, bool) {
+	var (
+		x     = make(map[int]float64)
+		found bool
+		box   = core.BBox{
+			Min: core.Point{X: math.MaxFloat64, Y: math.MaxFloat64},
+			Max: core.Point{X: -math.MaxFloat64, Y: -math.MaxFloat64},
+		}
+	)
+
+	for _, t := range r.Tags {
+		switch {
+		case t.Code >= 10 && t.Code <= 18:
+			x[t.Code] = t.AsFloat()
+		case t.Code >= 20 && t.Code <= 28:
+			px, ok := x[t.Code-10]
+			if !ok {
+				continue
+			}
+			py := t.AsFloat()
+			box.Min.X, box.Min.Y = math.Min(box.Min.X, px), math.Min(box.Min.Y, py)
+			box.Max.X, box.Max.Y = math.Max(box.Max.X, px), math.Max(box.Max.Y, py)
+			found = true
+		}
+	}
+
+	if !found {
+		return core.BBox{}, false
+	}
+
+	return box, true
+}
@@ -0,0 +1,150 @@
+package entities
+
+import (
+	"iter"
+	"math"
+
+	"github.com/zooyer/dxf/core"
+)
+
+// Segment 多段线中相邻两个顶点之间的一段，凸度为 0 时为直线，否则为圆弧
+// 坐标与所属多段线的顶点相同，二维多段线为实体坐标系 (OCS) 中的点
+type Segment struct {
+	Start      core.Point
+	End        core.Point
+	Bulge      float64 // 凸度，正数为逆时针圆弧，负数为顺时针圆弧
+	StartWidth float64
+	EndWidth   float64
+}
+
+// IsArc 是否为圆弧段
+func (s Segment) IsArc() bool {
+	return s.Bulge != 0 && s.Start != s.End
+}
+
+// Angle 返回圆弧的圆心角 (弧度)，逆时针为正，直线段为 0
+func (s Segment) Angle() float64 {
+	if !s.IsArc() {
+		return 0
+	}
+	return 4 * math.Atan(s.Bulge)
+}
+
+// Center 返回圆弧的圆心，直线段返回中点
+func (s Segment) Center() core.Point {
+	mid := s.Start.Add(s.End).Mul(0.5)
+	if !s.IsArc() {
+		return mid
+	}
+	// 圆心在弦的中垂线上，到弦中点的距离为 弦长/2 · (1 - b²) / (2b)
+	d := s.End.Sub(s.Start)
+	k := (1 - s.Bulge*s.Bulge) / (4 * s.Bulge)
+	return mid.Add(core.Point{X: -d.Y * k, Y: d.X * k})
+}
+
+// Radius 返回圆弧的半径，直线段为 0
+func (s Segment) Radius() float64 {
+	if !s.IsArc() {
+		return 0
+	}
+	chord := math.Hypot(s.End.X-s.Start.X, s.End.Y-s.Start.Y)
+	return chord / (2 * math.Abs(math.Sin(s.Angle()/2)))
+}
+
+// Length 返回弧长或线段长度
+func (s Segment) Length() float64 {
+	if !s.IsArc() {
+		return s.End.Sub(s.Start).Length()
+	}
+	return s.Radius() * math.Abs(s.Angle())
+}
+
+// BBox 返回本段的包围盒，圆弧段包含其象限点
+func (s Segment) BBox() core.BBox {
+	return s.bbox(core.Identity())
+}
+
+// bbox 返回本段经过 m 变换后的包围盒
+func (s Segment) bbox(m core.Matrix) core.BBox {
+	if !s.IsArc() {
+		start := m.Apply(s.Start)
+		return core.BBox{Min: start, Max: start}.Extend(m.Apply(s.End))
+	}
+
+	// 顺时针圆弧按终点到起点的逆时针圆弧计算
+	center, r := s.Center(), s.Radius()
+	from := s.Start
+	if s.Bulge < 0 {
+		from = s.End
+	}
+	start := math.Atan2(from.Y-center.Y, from.X-center.X)
+	end := start + math.Abs(s.Angle())
+
+	u := m.ApplyVector(core.Point{X: r})
+	v := m.ApplyVector(core.Point{Y: r})
+	return conicBBox(m.Apply(core.Point{X: center.X, Y: center.Y, Z: s.Start.Z}), u, v, start, end)
+}
+
+// area 返回本段与原点围成的有向面积，逆时针为正；闭合多段线各段之和即为其面积
+func (s Segment) area() float64 {
+	a := (s.Start.X*s.End.Y - s.End.X*s.Start.Y) / 2
+	if s.IsArc() {
+		// 加上弦与圆弧之间的弓形面积
+		theta, r := s.Angle(), s.Radius()
+		a += r * r * (theta - math.Sin(theta)) / 2
+	}
+	return a
+}
+
+// segments 依次产出顶点之间的各段，closed 时包含最后一个顶点回到第一个顶点的一段
+// 顶点未设置宽度时使用 width 作为默认宽度
+func segments(vertices []Vertex, closed bool, width [2]float64) iter.Seq[Segment] {
+	return func(yield func(Segment) bool) {
+		n := len(vertices)
+		if !closed {
+			n--
+		}
+		for i := 0; i < n; i++ {
+			v, next := vertices[i], vertices[(i+1)%len(vertices)]
+			s := Segment{Start: v.Point, End: next.Point, Bulge: v.Bulge, StartWidth: v.StartWidth, EndWidth: v.EndWidth}
+			if s.StartWidth == 0 && s.EndWidth == 0 {
+				s.StartWidth, s.EndWidth = width[0], width[1]
+			}
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// polylineBBox 返回顶点坐标经过 m 变换后的包围盒，圆弧段按实际圆弧计算
+func polylineBBox(vertices []Vertex, closed bool, m core.Matrix) core.BBox {
+	if len(vertices) == 0 {
+		return core.BBox{}
+	}
+
+	p := m.Apply(vertices[0].Point)
+	box := core.BBox{Min: p, Max: p}
+	for s := range segments(vertices, closed, [2]float64{}) {
+		b := s.bbox(m)
+		box = box.Extend(b.Min).Extend(b.Max)
+	}
+	return box
+}
+
+// polylineLength 返回各段长度之和
+func polylineLength(vertices []Vertex, closed bool) (length float64) {
+	for s := range segments(vertices, closed, [2]float64{}) {
+		length += s.Length()
+	}
+	return
+}
+
+// polylineArea 返回按闭合计算的面积 (不区分方向)
+func polylineArea(vertices []Vertex) float64 {
+	var area float64
+	for s := range segments(vertices, true, [2]float64{}) {
+		area += s.area()
+	}
+	return math.Abs(area)
+}
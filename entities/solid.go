@@ -0,0 +1,136 @@
+package entities
+
+import (
+	"github.com/zooyer/dxf/core"
+)
+
+// Solid 二维填充 SOLID 或宽线 TRACE，由 3 或 4 个角点构成的填充四边形
+// 角点按 1、2、4、3 的顺序连接 (Z 字形)，只有 3 个角点时第 4 个角点与第 3 个相同
+type Solid struct {
+	BaseEntity
+	Corners   [4]core.Point // 组码 10~13，角点 (实体坐标系 OCS)
+	Thickness float64       // 组码 39，厚度
+	Extrusion core.Point    // 组码 210/220/230，拉伸方向，默认 (0, 0, 1)
+}
+
+func init() {
+	for _, name := range []string{"SOLID", "TRACE"} {
+		Register(name, func() Entity {
+			return &Solid{BaseEntity: BaseEntity{TypeName: name}, Extrusion: core.ZAxis}
+		})
+	}
+}
+
+// NewSolid 创建一个填充四边形，角点按轮廓顺序给出；只给出 3 个角点时为三角形
+func NewSolid(layer string, corners ...core.Point) *Solid {
+	s := &Solid{BaseEntity: BaseEntity{TypeName: "SOLID", LayerName: layer}, Extrusion: core.ZAxis}
+	switch len(corners) {
+	case 3:
+		s.Corners = [4]core.Point{corners[0], corners[1], corners[2], corners[2]}
+	case 4:
+		// 轮廓顺序 1、2、3、4 对应存储顺序 1、2、4、3
+		s.Corners = [4]core.Point{corners[0], corners[1], corners[3], corners[2]}
+	}
+	return s
+}
+
+// parseCorner 解析组码 10~13、20~23、30~33 表示的角点坐标
+func parseCorner(corners *[4]core.Point, t core.Tag) bool {
+	i := t.Code % 10
+	if t.Code < 10 || t.Code > 33 || i > 3 {
+		return false
+	}
+	switch t.Code / 10 {
+	case 1:
+		corners[i].X = t.AsFloat()
+	case 2:
+		corners[i].Y = t.AsFloat()
+	case 3:
+		corners[i].Z = t.AsFloat()
+	}
+	return true
+}
+
+func (s *Solid) Parse(scanner *core.Scanner) error {
+	for _, t := range scanner.ReadEntity() {
+		if parseCorner(&s.Corners, t) {
+			continue
+		}
+		switch t.Code {
+		case 39:
+			s.Thickness = t.AsFloat()
+		case 210:
+			s.Extrusion.X = t.AsFloat()
+		case 220:
+			s.Extrusion.Y = t.AsFloat()
+		case 230:
+			s.Extrusion.Z = t.AsFloat()
+		default:
+			s.ParseTag(t)
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *Solid) Write(w *core.Writer) error {
+	s.WriteBase(w, "AcDbTrace")
+	if s.Thickness != 0 {
+		w.WriteFloat(39, s.Thickness)
+	}
+	for i, p := range s.Corners {
+		w.WritePoint(10+i, p)
+	}
+	writeExtrusion(w, s.Extrusion)
+	s.WriteExtra(w)
+	return w.Err()
+}
+
+// Triangle 是否为三角形 (第 3、4 个角点重合)
+func (s *Solid) Triangle() bool {
+	return s.Corners[2] == s.Corners[3]
+}
+
+// Outline 返回按轮廓顺序排列的世界坐标角点，三角形只有 3 个
+func (s *Solid) Outline() []core.Point {
+	m := core.ArbitraryAxis(s.Extrusion)
+	outline := []core.Point{m.Apply(s.Corners[0]), m.Apply(s.Corners[1]), m.Apply(s.Corners[3])}
+	if !s.Triangle() {
+		outline = append(outline, m.Apply(s.Corners[2]))
+	}
+	return outline
+}
+
+// Transformed 返回变换后的填充四边形
+func (s *Solid) Transformed(m core.Matrix) Entity {
+	c := *s
+	m, c.Extrusion = ocsTransform(m, s.Extrusion)
+	for i, p := range s.Corners {
+		c.Corners[i] = m.Apply(p)
+	}
+	return &c
+}
+
+// BBox 返回世界坐标下的包围盒，有厚度时包含沿拉伸方向拉伸出的顶面
+func (s *Solid) BBox() core.BBox {
+	box := cornersBBox(s.Corners, core.ArbitraryAxis(s.Extrusion))
+	if s.Thickness == 0 {
+		return box
+	}
+
+	n := s.Extrusion.Normalize()
+	if n == (core.Point{}) {
+		n = core.ZAxis
+	}
+	offset := n.Mul(s.Thickness)
+	return box.Extend(box.Min.Add(offset)).Extend(box.Max.Add(offset))
+}
+
+// cornersBBox 返回角点经过 m 变换后的包围盒
+func cornersBBox(corners [4]core.Point, m core.Matrix) core.BBox {
+	first := m.Apply(corners[0])
+	box := core.BBox{Min: first, Max: first}
+	for _, p := range corners[1:] {
+		box = box.Extend(m.Apply(p))
+	}
+	return box
+}
@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/zooyer/dxf/core"
+)
+
+func TestSolid_Parse(t *testing.T) {
+	// 拉伸方向为 (0,0,-1) 的三角形 TRACE：OCS 的 X 轴指向世界坐标 -X
+	data := "0\nTRACE\n5\n60\n8\nMULLION\n100\nAcDbTrace\n39\n5\n" +
+		"10\n0\n20\n0\n30\n0\n11\n4\n21\n0\n31\n0\n12\n0\n22\n2\n32\n0\n13\n0\n23\n2\n33\n0\n" +
+		"210\n0\n220\n0\n230\n-1\n0\nEOF\n"
+
+	ent, _ := parseEntity(t, data)
+	s := ent.(*Solid)
+	if s.Type() != "TRACE" || s.Thickness != 5 || !s.Triangle() || s.Corners[1] != (core.Point{X: 4}) {
+		t.Fatalf("TRACE 解析不正确: %+v", s)
+	}
+	if outline := s.Outline(); len(outline) != 3 || !nearPoint(outline[1], core.Point{X: -4}) {
+		t.Errorf("轮廓不正确: %+v", outline)
+	}
+	// 厚度沿拉伸方向 (0,0,-1) 拉伸到 Z = -5
+	if box := s.BBox(); !nearPoint(box.Min, core.Point{X: -4, Z: -5}) || !nearPoint(box.Max, core.Point{Y: 2}) {
+		t.Errorf("包围盒不正确: %+v", box)
+	}
+
+	var buf bytes.Buffer
+	w := core.NewWriter(&buf)
+	if err := s.Write(w); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Flush()
+	again, _ := parseEntity(t, buf.String())
+	if q := again.(*Solid); q.Type() != "TRACE" || q.Corners != s.Corners || q.Extrusion != s.Extrusion {
+		t.Errorf("写出后重新解析不正确:\n%s", buf.String())
+	}
+
+	// NewSolid 按轮廓顺序给出角点
+	square := NewSolid("0", core.Point{}, core.Point{X: 1}, core.Point{X: 1, Y: 1}, core.Point{Y: 1})
+	if outline := square.Outline(); outline[2] != (core.Point{X: 1, Y: 1}) || outline[3] != (core.Point{Y: 1}) {
+		t.Errorf("NewSolid 轮廓不正确: %+v", outline)
+	}
+}
+
+func TestFace_Parse(t *testing.T) {
+	data := "0\n3DFACE\n8\nGLASS\n10\n0\n20\n0\n30\n0\n11\n1\n21\n0\n31\n1\n12\n1\n22\n1\n32\n2\n13\n0\n23\n1\n33\n0\n70\n5\n" +
+		"0\nPOINT\n8\nHW\n10\n3\n20\n4\n30\n5\n0\nEOF\n"
+
+	ent, s := parseEntity(t, data)
+	f := ent.(*Face)
+	if f.Triangle() || f.Corners[2] != (core.Point{X: 1, Y: 1, Z: 2}) {
+		t.Fatalf("3DFACE 解析不正确: %+v", f)
+	}
+	if f.EdgeVisible(0) || !f.EdgeVisible(1) || f.EdgeVisible(2) || !f.EdgeVisible(3) {
+		t.Errorf("不可见边不正确: %d", f.Flags)
+	}
+	if box := f.BBox(); box.Max != (core.Point{X: 1, Y: 1, Z: 2}) {
+		t.Errorf("包围盒不正确: %+v", box)
+	}
+
+	if !s.Next() {
+		t.Fatal(s.Err())
+	}
+	p := CreateEntity(s.LastTag.Value)
+	if err := p.Parse(s); err != nil {
+		t.Fatal(err)
+	}
+	if box := p.BBox(); box.Min != (core.Point{X: 3, Y: 4, Z: 5}) {
+		t.Errorf("POINT 包围盒不正确: %+v", box)
+	}
+}
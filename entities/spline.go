@@ -0,0 +1,231 @@
+package entities
+
+import (
+	"github.com/zooyer/dxf/core"
+)
+
+// 样条标志 (组码 70)
+const (
+	SplineClosed   = 1  // 闭合
+	SplinePeriodic = 2  // 周期
+	SplineRational = 4  // 有理
+	SplinePlanar   = 8  // 平面
+	SplineLinear   = 16 // 线性 (同时设置平面)
+)
+
+// Spline 样条曲线 (NURBS)，坐标均为世界坐标
+type Spline struct {
+	BaseEntity
+	Normal           core.Point   // 组码 210/220/230，平面样条的法向
+	Flags            int          // 组码 70，样条标志
+	Degree           int          // 组码 71，阶数
+	Knots            []float64    // 组码 40，节点
+	ControlPoints    []core.Point // 组码 10/20/30，控制点
+	Weights          []float64    // 组码 41，权重，为空时均为 1
+	FitPoints        []core.Point // 组码 11/21/31，拟合点
+	StartTangent     core.Point   // 组码 12/22/32，起点切向
+	EndTangent       core.Point   // 组码 13/23/33，终点切向
+	KnotTolerance    float64      // 组码 42，节点公差
+	ControlTolerance float64      // 组码 43，控制点公差
+	FitTolerance     float64      // 组码 44，拟合公差
+}
+
+func init() {
+	Register("SPLINE", func() Entity {
+		return &Spline{BaseEntity: BaseEntity{TypeName: "SPLINE"}}
+	})
+}
+
+// NewSpline 创建两端夹紧、节点均匀的非有理样条
+func NewSpline(layer string, degree int, points ...core.Point) *Spline {
+	s := &Spline{
+		BaseEntity:    BaseEntity{TypeName: "SPLINE", LayerName: layer},
+		Degree:        degree,
+		ControlPoints: points,
+	}
+	if c := s.curve(); c != nil {
+		s.Degree, s.Knots = c.degree, c.knots
+	}
+	return s
+}
+
+func (s *Spline) Parse(scanner *core.Scanner) error {
+	for _, t := range scanner.ReadEntity() {
+		switch t.Code {
+		case 210:
+			s.Normal.X = t.AsFloat()
+		case 220:
+			s.Normal.Y = t.AsFloat()
+		case 230:
+			s.Normal.Z = t.AsFloat()
+		case 70:
+			s.Flags = t.AsInt()
+		case 71:
+			s.Degree = t.AsInt()
+		case 72, 73, 74:
+			// 节点、控制点与拟合点的数量由切片长度决定
+		case 42:
+			s.KnotTolerance = t.AsFloat()
+		case 43:
+			s.ControlTolerance = t.AsFloat()
+		case 44:
+			s.FitTolerance = t.AsFloat()
+		case 12:
+			s.StartTangent.X = t.AsFloat()
+		case 22:
+			s.StartTangent.Y = t.AsFloat()
+		case 32:
+			s.StartTangent.Z = t.AsFloat()
+		case 13:
+			s.EndTangent.X = t.AsFloat()
+		case 23:
+			s.EndTangent.Y = t.AsFloat()
+		case 33:
+			s.EndTangent.Z = t.AsFloat()
+		case 40:
+			s.Knots = append(s.Knots, t.AsFloat())
+		case 41:
+			s.Weights = append(s.Weights, t.AsFloat())
+		case 10:
+			s.ControlPoints = append(s.ControlPoints, core.Point{X: t.AsFloat()})
+		case 20, 30:
+			if n := len(s.ControlPoints); n > 0 {
+				setCoord(&s.ControlPoints[n-1], t)
+			}
+		case 11:
+			s.FitPoints = append(s.FitPoints, core.Point{X: t.AsFloat()})
+		case 21, 31:
+			if n := len(s.FitPoints); n > 0 {
+				setCoord(&s.FitPoints[n-1], t)
+			}
+		default:
+			s.ParseTag(t)
+		}
+	}
+	return scanner.Err()
+}
+
+// setCoord 按组码设置点的 Y (2x) 或 Z (3x) 坐标
+func setCoord(p *core.Point, t core.Tag) {
+	if t.Code/10 == 2 {
+		p.Y = t.AsFloat()
+	} else {
+		p.Z = t.AsFloat()
+	}
+}
+
+func (s *Spline) Write(w *core.Writer) error {
+	s.WriteBase(w, "AcDbSpline")
+	if s.Normal != (core.Point{}) {
+		w.WritePoint(210, s.Normal)
+	}
+	w.WriteInt(70, s.Flags)
+	w.WriteInt(71, s.Degree)
+	w.WriteInt(72, len(s.Knots))
+	w.WriteInt(73, len(s.ControlPoints))
+	w.WriteInt(74, len(s.FitPoints))
+	w.WriteFloat(42, tolerance(s.KnotTolerance))
+	w.WriteFloat(43, tolerance(s.ControlTolerance))
+	if len(s.FitPoints) > 0 {
+		w.WriteFloat(44, tolerance(s.FitTolerance))
+	}
+	if s.StartTangent != (core.Point{}) {
+		w.WritePoint(12, s.StartTangent)
+	}
+	if s.EndTangent != (core.Point{}) {
+		w.WritePoint(13, s.EndTangent)
+	}
+	for _, k := range s.Knots {
+		w.WriteFloat(40, k)
+	}
+	for _, weight := range s.Weights {
+		w.WriteFloat(41, weight)
+	}
+	for _, p := range s.ControlPoints {
+		w.WritePoint(10, p)
+	}
+	for _, p := range s.FitPoints {
+		w.WritePoint(11, p)
+	}
+	s.WriteExtra(w)
+	return w.Err()
+}
+
+// tolerance 返回公差，未设置时为 CAD 默认的 1e-10
+func tolerance(t float64) float64 {
+	if t == 0 {
+		return 1e-10
+	}
+	return t
+}
+
+// Closed 是否闭合
+func (s *Spline) Closed() bool {
+	return s.Flags&SplineClosed != 0
+}
+
+// curve 返回样条的 NURBS 表示，没有足够的控制点时返回 nil
+func (s *Spline) curve() *nurbs {
+	return newNURBS(s.Degree, s.Knots, s.ControlPoints, s.Weights)
+}
+
+// Domain 返回参数的定义域，没有控制点时为 0
+func (s *Spline) Domain() (start, end float64) {
+	if c := s.curve(); c != nil {
+		return c.domain()
+	}
+	return 0, 0
+}
+
+// PointAt 返回参数 t 处的点，t 超出定义域时取端点
+func (s *Spline) PointAt(t float64) core.Point {
+	if c := s.curve(); c != nil {
+		return c.at(t)
+	}
+	if len(s.ControlPoints) > 0 {
+		return s.ControlPoints[0]
+	}
+	if len(s.FitPoints) > 0 {
+		return s.FitPoints[0]
+	}
+	return core.Point{}
+}
+
+// Flatten 将样条离散为折线，曲线到折线的距离不超过 tolerance，tolerance <= 0 时按控制点范围自动选择精度
+// 只有拟合点的样条按拟合点连线近似
+func (s *Spline) Flatten(tolerance float64) []core.Point {
+	if c := s.curve(); c != nil {
+		return c.flatten(tolerance)
+	}
+	if len(s.ControlPoints) > 0 {
+		return s.ControlPoints
+	}
+	return s.FitPoints
+}
+
+// BBox 返回曲线本身 (而非控制多边形) 的包围盒
+func (s *Spline) BBox() core.BBox {
+	return pointsBBox(s.Flatten(0))
+}
+
+// Transformed 返回变换后的样条，NURBS 在仿射变换下只需变换控制点
+func (s *Spline) Transformed(m core.Matrix) Entity {
+	c := *s
+	transform := func(points []core.Point) []core.Point {
+		result := make([]core.Point, len(points))
+		for i, p := range points {
+			result[i] = m.Apply(p)
+		}
+		return result
+	}
+
+	c.ControlPoints, c.FitPoints = transform(s.ControlPoints), transform(s.FitPoints)
+	c.StartTangent, c.EndTangent = m.ApplyVector(s.StartTangent), m.ApplyVector(s.EndTangent)
+	if s.Normal != (core.Point{}) {
+		c.Normal = m.ApplyVector(s.Normal).Normalize()
+	}
+	c.Knots = append([]float64(nil), s.Knots...)
+	c.Weights = append([]float64(nil), s.Weights...)
+
+	return &c
+}
@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/zooyer/dxf/core"
+)
+
+func TestSpline_PointAt(t *testing.T) {
+	// 二次有理 B 样条精确表示四分之一圆
+	s := &Spline{
+		BaseEntity:    BaseEntity{TypeName: "SPLINE"},
+		Flags:         SplineRational | SplinePlanar,
+		Degree:        2,
+		Knots:         []float64{0, 0, 0, 1, 1, 1},
+		ControlPoints: []core.Point{{X: 1}, {X: 1, Y: 1}, {Y: 1}},
+		Weights:       []float64{1, math.Sqrt2 / 2, 1},
+	}
+	for _, u := range []float64{0, 0.1, 0.5, 0.9, 1} {
+		if r := s.PointAt(u).Length(); math.Abs(r-1) > 1e-12 {
+			t.Errorf("参数 %v 处的点不在单位圆上: r=%v", u, r)
+		}
+	}
+
+	// 包围盒按曲线计算，不包含控制点 (1,1)
+	box := s.BBox()
+	if !nearPoint(box.Min, core.Point{}) || math.Abs(box.Max.X-1) > 1e-9 || math.Abs(box.Max.Y-1) > 1e-9 {
+		t.Errorf("包围盒不正确: %+v", box)
+	}
+	for _, p := range s.Flatten(1e-4) {
+		if math.Abs(p.Length()-1) > 1e-12 {
+			t.Fatalf("离散点不在曲线上: %+v", p)
+		}
+	}
+}
+
+func TestSpline_Parse(t *testing.T) {
+	// 三次样条：控制多边形高出曲线，曲线最高点为 y=0.75
+	data := "0\nSPLINE\n5\n50\n8\nGRILLE\n100\nAcDbSpline\n210\n0\n220\n0\n230\n1\n70\n8\n71\n3\n72\n8\n73\n4\n74\n0\n42\n1e-10\n43\n1e-10\n" +
+		"40\n0\n40\n0\n40\n0\n40\n0\n40\n1\n40\n1\n40\n1\n40\n1\n" +
+		"10\n0\n20\n0\n30\n0\n10\n1\n20\n1\n30\n0\n10\n2\n20\n1\n30\n0\n10\n3\n20\n0\n30\n0\n0\nEOF\n"
+
+	ent, _ := parseEntity(t, data)
+	s := ent.(*Spline)
+	if s.Degree != 3 || len(s.Knots) != 8 || len(s.ControlPoints) != 4 || s.ControlPoints[2] != (core.Point{X: 2, Y: 1}) {
+		t.Fatalf("样条解析不正确: %+v", s)
+	}
+	if p := s.PointAt(0.5); !nearPoint(p, core.Point{X: 1.5, Y: 0.75}) {
+		t.Errorf("中点不正确: %+v", p)
+	}
+	if box := s.BBox(); math.Abs(box.Max.Y-0.75) > 1e-6 || box.Max.X != 3 {
+		t.Errorf("包围盒不正确: %+v", box)
+	}
+
+	moved := s.Transformed(core.Translation(core.Point{X: 10})).(*Spline)
+	if p := moved.PointAt(0.5); !nearPoint(p, core.Point{X: 11.5, Y: 0.75}) {
+		t.Errorf("平移后的中点不正确: %+v", p)
+	}
+
+	var buf bytes.Buffer
+	w := core.NewWriter(&buf)
+	if err := s.Write(w); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Flush()
+	again, _ := parseEntity(t, buf.String())
+	if q := again.(*Spline); len(q.Knots) != 8 || len(q.ControlPoints) != 4 || q.Flags != 8 {
+		t.Errorf("写出后重新解析不正确:\n%s", buf.String())
+	}
+}
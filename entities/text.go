@@ -0,0 +1,275 @@
+package entities
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/zooyer/dxf/core"
+)
+
+// 单行文字的水平对齐方式 (组码 72)
+const (
+	TextAlignLeft   = 0
+	TextAlignCenter = 1
+	TextAlignRight  = 2
+	TextAlignFit    = 3 // 对齐：在两点之间调整高度
+	TextAlignMiddle = 4 // 中间：水平、垂直都居中
+	TextAlignFill   = 5 // 布满：在两点之间调整宽度
+)
+
+// 单行文字的垂直对齐方式 (组码 73)
+const (
+	TextVAlignBaseline = 0
+	TextVAlignBottom   = 1
+	TextVAlignMiddle   = 2
+	TextVAlignTop      = 3
+)
+
+type Text struct {
+	BaseEntity
+	Text         string     // 组码 1，文字内容 (含 %%c 等控制码)
+	Location     core.Point // 组码 10/20/30，第一对齐点 (左下角基线位置)
+	AlignPoint   core.Point // 组码 11/21/31，第二对齐点，非左对齐时为定位点
+	Height       float64    // 组码 40，文字高度
+	Rotation     float64    // 组码 50，旋转角度 (度)
+	WidthFactor  float64    // 组码 41，宽度因子，默认 1
+	Oblique      float64    // 组码 51，倾斜角度 (度)
+	Style        string     // 组码 7，文字样式，默认 "STANDARD"
+	Generation   int        // 组码 71，2 表示左右反向，4 表示上下倒置
+	HAlign       int        // 组码 72，水平对齐方式
+	VAlign       int        // 组码 73，垂直对齐方式
+	Thickness    float64    // 组码 39，厚度
+	Extrusion    core.Point // 组码 210/220/230，拉伸方向，默认 (0, 0, 1)
+	hasAlignment bool       // 是否读到了组码 11
+}
+
+func init() {
+	Register("TEXT", func() Entity {
+		return &Text{BaseEntity: BaseEntity{TypeName: "TEXT"}, WidthFactor: 1, Extrusion: core.ZAxis}
+	})
+}
+
+// NewText 创建一个左对齐的单行文字
+func NewText(layer string, location core.Point, height float64, text string) *Text {
+	return &Text{
+		BaseEntity:  BaseEntity{TypeName: "TEXT", LayerName: layer},
+		Text:        text,
+		Location:    location,
+		Height:      height,
+		WidthFactor: 1,
+		Extrusion:   core.ZAxis,
+	}
+}
+
+func (t *Text) Parse(s *core.Scanner) error {
+	for _, tag := range s.ReadEntity() {
+		switch tag.Code {
+		case 1:
+			t.Text = tag.Value
+		case 10:
+			t.Location.X = tag.AsFloat()
+		case 20:
+			t.Location.Y = tag.AsFloat()
+		case 30:
+			t.Location.Z = tag.AsFloat()
+		case 11:
+			t.AlignPoint.X = tag.AsFloat()
+			t.hasAlignment = true
+		case 21:
+			t.AlignPoint.Y = tag.AsFloat()
+		case 31:
+			t.AlignPoint.Z = tag.AsFloat()
+		case 40:
+			t.Height = tag.AsFloat()
+		case 50:
+			t.Rotation = tag.AsFloat()
+		case 41:
+			t.WidthFactor = tag.AsFloat()
+		case 51:
+			t.Oblique = tag.AsFloat()
+		case 7:
+			t.Style = tag.Value
+		case 71:
+			t.Generation = tag.AsInt()
+		case 72:
+			t.HAlign = tag.AsInt()
+		case 73:
+			t.VAlign = tag.AsInt()
+		case 39:
+			t.Thickness = tag.AsFloat()
+		case 210:
+			t.Extrusion.X = tag.AsFloat()
+		case 220:
+			t.Extrusion.Y = tag.AsFloat()
+		case 230:
+			t.Extrusion.Z = tag.AsFloat()
+		default:
+			t.ParseTag(tag)
+		}
+	}
+	return s.Err()
+}
+
+func (t *Text) Write(w *core.Writer) error {
+	t.WriteBase(w, "AcDbText")
+	if t.Thickness != 0 {
+		w.WriteFloat(39, t.Thickness)
+	}
+	w.WritePoint(10, t.Location)
+	w.WriteFloat(40, t.Height)
+	w.WriteString(1, t.Text)
+	if t.Rotation != 0 {
+		w.WriteFloat(50, t.Rotation)
+	}
+	if t.WidthFactor != 1 {
+		w.WriteFloat(41, t.WidthFactor)
+	}
+	if t.Oblique != 0 {
+		w.WriteFloat(51, t.Oblique)
+	}
+	if t.Style != "" {
+		w.WriteString(7, t.Style)
+	}
+	if t.Generation != 0 {
+		w.WriteInt(71, t.Generation)
+	}
+	if t.HAlign != 0 {
+		w.WriteInt(72, t.HAlign)
+	}
+	if t.aligned() {
+		w.WritePoint(11, t.AlignPoint)
+	}
+	writeExtrusion(w, t.Extrusion)
+	w.WriteString(100, "AcDbText")
+	if t.VAlign != 0 {
+		w.WriteInt(73, t.VAlign)
+	}
+	t.WriteExtra(w)
+	return w.Err()
+}
+
+// aligned 是否使用第二对齐点定位
+func (t *Text) aligned() bool {
+	return t.hasAlignment || t.HAlign != 0 || t.VAlign != 0
+}
+
+// PlainText 返回去掉 %% 控制码后的文字
+func (t *Text) PlainText() string {
+	return DecodeSpecial(t.Text)
+}
+
+// BBox 按字符数估算文字的包围盒 (没有字体信息，中文按 1 倍字高、其他字符按 0.6 倍字高计算宽度)
+func (t *Text) BBox() core.BBox {
+	var (
+		width  = textWidth(t.PlainText(), t.Height) * t.WidthFactor
+		height = t.Height
+		anchor = t.Location
+		angle  = t.Rotation
+		x, y   float64 // 左下角相对定位点的偏移
+	)
+
+	if t.aligned() {
+		anchor = t.AlignPoint
+	}
+
+	switch t.HAlign {
+	case TextAlignCenter:
+		x = -width / 2
+	case TextAlignRight:
+		x = -width
+	case TextAlignMiddle:
+		x, y = -width/2, -height/2
+	case TextAlignFit, TextAlignFill:
+		// 文字布满两个对齐点之间
+		d := t.AlignPoint.Sub(t.Location)
+		anchor, width = t.Location, math.Hypot(d.X, d.Y)
+		angle = math.Atan2(d.Y, d.X) * 180.0 / math.Pi
+	}
+	if t.HAlign != TextAlignMiddle {
+		switch t.VAlign {
+		case TextVAlignBottom:
+			y = -0.2 * height // 下行字母的高度
+		case TextVAlignMiddle:
+			y = -height / 2
+		case TextVAlignTop:
+			y = -height
+		}
+	}
+
+	m := core.ArbitraryAxis(t.Extrusion).
+		Multiply(core.Translation(anchor)).
+		Multiply(core.RotationZ(angle))
+
+	return m.ApplyBBox(core.BBox{Min: core.Point{X: x, Y: y}, Max: core.Point{X: x + width, Y: y + height}})
+}
+
+// Transformed 返回变换后的文字，高度按比例缩放
+func (t *Text) Transformed(m core.Matrix) Entity {
+	c := *t
+	m, c.Extrusion = ocsTransform(m, t.Extrusion)
+	c.Location = m.Apply(t.Location)
+	c.AlignPoint = m.Apply(t.AlignPoint)
+	c.Rotation = transformAngle(m, t.Rotation)
+	c.Height = t.Height * scaleOf(m)
+	return &c
+}
+
+// textWidth 按字符数估算文字宽度
+func textWidth(text string, height float64) float64 {
+	var width float64
+	for _, r := range text {
+		if r > unicode.MaxLatin1 {
+			width += height
+		} else {
+			width += height * 0.6
+		}
+	}
+	return width
+}
+
+// DecodeSpecial 转换 TEXT 中的 %% 控制码：%%c 直径符号、%%d 度、%%p 正负号、%%nnn 字符编码
+// 上划线、下划线开关 (%%o、%%u) 被去掉
+func DecodeSpecial(text string) string {
+	if !strings.Contains(text, "%%") {
+		return text
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] != '%' || !strings.HasPrefix(text[i:], "%%") || i+2 >= len(text) {
+			b.WriteByte(text[i])
+			continue
+		}
+
+		switch c := text[i+2]; c {
+		case 'c', 'C':
+			b.WriteRune('Ø')
+		case 'd', 'D':
+			b.WriteRune('°')
+		case 'p', 'P':
+			b.WriteRune('±')
+		case '%':
+			b.WriteByte('%')
+		case 'o', 'O', 'u', 'U', 'k', 'K':
+		default:
+			j := i + 2
+			for j < len(text) && j < i+5 && text[j] >= '0' && text[j] <= '9' {
+				j++
+			}
+			if j == i+2 {
+				b.WriteString("%%")
+				i++
+				continue
+			}
+			n, _ := strconv.Atoi(text[i+2 : j])
+			b.WriteRune(rune(n))
+			i = j - 1
+			continue
+		}
+		i += 2
+	}
+
+	return b.String()
+}
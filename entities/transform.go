@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"math"
+
+	"github.com/zooyer/dxf/core"
+)
+
+// Transformer 可以进行仿射变换的实体，Transformed 返回变换后的副本，原实体保持不变
+type Transformer interface {
+	Transformed(m core.Matrix) Entity
+}
+
+// ocsTransform 返回实体坐标系中的变换：原 OCS 坐标 → 世界坐标变换 m → 新 OCS 坐标，以及新的拉伸方向
+// 镜像时返回矩阵的行列式小于 0，凸度等有方向的量需要取反
+func ocsTransform(m core.Matrix, extrusion core.Point) (core.Matrix, core.Point) {
+	normal := extrusion.Normalize()
+	if normal == (core.Point{}) {
+		normal = core.ZAxis
+	}
+	normal = m.ApplyVector(normal).Normalize()
+
+	inv, _ := core.ArbitraryAxis(normal).Inverse()
+	return inv.Multiply(m).Multiply(core.ArbitraryAxis(extrusion)), normal
+}
+
+// transformAngle 返回 angle (度) 方向经过变换后的角度，用于旋转角、标注方向等
+func transformAngle(m core.Matrix, angle float64) float64 {
+	rad := angle * math.Pi / 180.0
+	v := m.ApplyVector(core.Point{X: math.Cos(rad), Y: math.Sin(rad)})
+	return math.Atan2(v.Y, v.X) * 180.0 / math.Pi
+}
+
+// scaleOf 返回变换后 Y 轴的长度，用于缩放文字高度等尺寸
+func scaleOf(m core.Matrix) float64 {
+	v := m.ApplyVector(core.Point{Y: 1})
+	return math.Hypot(v.X, v.Y)
+}
@@ -0,0 +1,11 @@
+package entities
+
+import "github.com/zooyer/dxf/core"
+
+// Vertex 多段线顶点，LWPOLYLINE 与 POLYLINE 共用
+type Vertex struct {
+	core.Point         // 顶点坐标，二维多段线为实体坐标系 (OCS) 中的点，可直接访问 X/Y/Z
+	StartWidth float64 // 组码 40，本段起点宽度
+	EndWidth   float64 // 组码 41，本段终点宽度
+	Bulge      float64 // 组码 42，凸度：本段圆弧圆心角四分之一的正切，0 为直线，负数为顺时针
+}
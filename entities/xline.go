@@ -0,0 +1,151 @@
+package entities
+
+import (
+	"math"
+
+	"github.com/zooyer/dxf/core"
+)
+
+// XLine 构造线，经过 Origin 沿 Direction 双向无限延伸
+type XLine struct {
+	BaseEntity
+	Origin    core.Point // 组码 10/20/30，基点 (世界坐标)
+	Direction core.Point // 组码 11/21/31，单位方向向量
+}
+
+// Ray 射线，从 Origin 沿 Direction 单向无限延伸
+type Ray struct {
+	XLine
+}
+
+func init() {
+	Register("XLINE", func() Entity { return &XLine{BaseEntity: BaseEntity{TypeName: "XLINE"}} })
+	Register("RAY", func() Entity { return &Ray{XLine{BaseEntity: BaseEntity{TypeName: "RAY"}}} })
+}
+
+// NewXLine 创建一条构造线
+func NewXLine(layer string, base, direction core.Point) *XLine {
+	return &XLine{BaseEntity: BaseEntity{TypeName: "XLINE", LayerName: layer}, Origin: base, Direction: direction.Normalize()}
+}
+
+// NewRay 创建一条射线
+func NewRay(layer string, base, direction core.Point) *Ray {
+	r := &Ray{*NewXLine(layer, base, direction)}
+	r.TypeName = "RAY"
+	return r
+}
+
+func (x *XLine) Parse(s *core.Scanner) error {
+	for _, t := range s.ReadEntity() {
+		switch t.Code {
+		case 10:
+			x.Origin.X = t.AsFloat()
+		case 20:
+			x.Origin.Y = t.AsFloat()
+		case 30:
+			x.Origin.Z = t.AsFloat()
+		case 11:
+			x.Direction.X = t.AsFloat()
+		case 21:
+			x.Direction.Y = t.AsFloat()
+		case 31:
+			x.Direction.Z = t.AsFloat()
+		default:
+			x.ParseTag(t)
+		}
+	}
+	return s.Err()
+}
+
+func (x *XLine) Write(w *core.Writer) error {
+	x.WriteBase(w, "AcDbXline")
+	x.writeLine(w)
+	return w.Err()
+}
+
+func (r *Ray) Write(w *core.Writer) error {
+	r.WriteBase(w, "AcDbRay")
+	r.writeLine(w)
+	return w.Err()
+}
+
+// writeLine 写出构造线与射线共有的组码
+func (x *XLine) writeLine(w *core.Writer) {
+	w.WritePoint(10, x.Origin)
+	w.WritePoint(11, x.Direction)
+	x.WriteExtra(w)
+}
+
+// Transformed 返回变换后的构造线
+func (x *XLine) Transformed(m core.Matrix) Entity {
+	c := *x
+	c.Origin, c.Direction = m.Apply(x.Origin), m.ApplyVector(x.Direction).Normalize()
+	return &c
+}
+
+// Transformed 返回变换后的射线
+func (r *Ray) Transformed(m core.Matrix) Entity {
+	return &Ray{*r.XLine.Transformed(m).(*XLine)}
+}
+
+// BBox 返回无限的包围盒：沿方向延伸的坐标轴上为 ±Inf，可用 core.BBox.Bounded 判断
+func (x *XLine) BBox() core.BBox {
+	return unboundedBBox(x.Origin, x.Direction, math.Inf(-1), math.Inf(1))
+}
+
+// BBox 返回无限的包围盒：方向为正的坐标轴上 Max 为 +Inf，为负的坐标轴上 Min 为 -Inf
+func (r *Ray) BBox() core.BBox {
+	return unboundedBBox(r.Origin, r.Direction, 0, math.Inf(1))
+}
+
+// Clip 返回构造线在 box 内的部分 (按 X、Y 裁剪)，不相交时 ok 为 false
+func (x *XLine) Clip(box core.BBox) (start, end core.Point, ok bool) {
+	return clipLine(x.Origin, x.Direction, math.Inf(-1), math.Inf(1), box)
+}
+
+// Clip 返回射线在 box 内的部分 (按 X、Y 裁剪)，不相交时 ok 为 false
+func (r *Ray) Clip(box core.BBox) (start, end core.Point, ok bool) {
+	return clipLine(r.Origin, r.Direction, 0, math.Inf(1), box)
+}
+
+// unboundedBBox 返回 base + t·dir (t 从 t0 到 t1) 的包围盒，t 可以为无穷大
+func unboundedBBox(base, dir core.Point, t0, t1 float64) core.BBox {
+	axis := func(b, d float64) (lo, hi float64) {
+		if d == 0 {
+			return b, b
+		}
+		lo, hi = b+d*t0, b+d*t1
+		return math.Min(lo, hi), math.Max(lo, hi)
+	}
+
+	var box core.BBox
+	box.Min.X, box.Max.X = axis(base.X, dir.X)
+	box.Min.Y, box.Max.Y = axis(base.Y, dir.Y)
+	box.Min.Z, box.Max.Z = axis(base.Z, dir.Z)
+	return box
+}
+
+// clipLine 用 Liang-Barsky 算法将 base + t·dir (t 从 t0 到 t1) 裁剪到 box 的 X、Y 范围内
+func clipLine(base, dir core.Point, t0, t1 float64, box core.BBox) (start, end core.Point, ok bool) {
+	p := [4]float64{-dir.X, dir.X, -dir.Y, dir.Y}
+	q := [4]float64{base.X - box.Min.X, box.Max.X - base.X, base.Y - box.Min.Y, box.Max.Y - base.Y}
+	for i := range p {
+		if p[i] == 0 {
+			// 平行于该边界，基点在范围外则整条线都在范围外
+			if q[i] < 0 {
+				return
+			}
+			continue
+		}
+		if t := q[i] / p[i]; p[i] < 0 {
+			t0 = math.Max(t0, t)
+		} else {
+			t1 = math.Min(t1, t)
+		}
+	}
+
+	if t0 > t1 || math.IsInf(t0, 0) || math.IsInf(t1, 0) {
+		return
+	}
+	return base.Add(dir.Mul(t0)), base.Add(dir.Mul(t1)), true
+}
@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zooyer/dxf/core"
+)
+
+func TestXLine_BBox(t *testing.T) {
+	// 水平构造线只在 X 方向无限
+	x := NewXLine("0", core.Point{X: 1, Y: 2}, core.Point{X: 1})
+	box := x.BBox()
+	if box.Bounded() || !math.IsInf(box.Min.X, -1) || !math.IsInf(box.Max.X, 1) || box.Min.Y != 2 || box.Max.Y != 2 {
+		t.Errorf("构造线包围盒不正确: %+v", box)
+	}
+
+	// 指向左下的射线：Max 为基点，Min 为 -Inf
+	r := NewRay("0", core.Point{X: 1, Y: 2}, core.Point{X: -1, Y: -1})
+	box = r.BBox()
+	if box.Bounded() || box.Max != (core.Point{X: 1, Y: 2}) || !math.IsInf(box.Min.X, -1) || !math.IsInf(box.Min.Y, -1) {
+		t.Errorf("射线包围盒不正确: %+v", box)
+	}
+}
+
+func TestXLine_Clip(t *testing.T) {
+	box := core.BBox{Max: core.Point{X: 10, Y: 10}}
+
+	x := NewXLine("0", core.Point{X: 5, Y: 5}, core.Point{X: 1, Y: 1})
+	if start, end, ok := x.Clip(box); !ok || !nearPoint(start, core.Point{}) || !nearPoint(end, core.Point{X: 10, Y: 10}) {
+		t.Errorf("构造线裁剪不正确: %+v %+v %v", start, end, ok)
+	}
+
+	// 射线从范围内出发，只保留正方向
+	r := NewRay("0", core.Point{X: 5, Y: 5}, core.Point{X: 1})
+	if start, end, ok := r.Clip(box); !ok || start != (core.Point{X: 5, Y: 5}) || end != (core.Point{X: 10, Y: 5}) {
+		t.Errorf("射线裁剪不正确: %+v %+v %v", start, end, ok)
+	}
+
+	// 背向范围的射线与范围外的水平线不相交
+	if _, _, ok := NewRay("0", core.Point{X: 20, Y: 5}, core.Point{X: 1}).Clip(box); ok {
+		t.Error("背向范围的射线不应相交")
+	}
+	if _, _, ok := NewXLine("0", core.Point{Y: 20}, core.Point{X: 1}).Clip(box); ok {
+		t.Error("范围外的水平线不应相交")
+	}
+
+	// 变换后仍为射线
+	moved := r.Transformed(core.Translation(core.Point{X: -5})).(*Ray)
+	if moved.Type() != "RAY" || moved.Origin != (core.Point{Y: 5}) {
+		t.Errorf("变换后的射线不正确: %+v", moved)
+	}
+}
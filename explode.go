@@ -0,0 +1,126 @@
+package dxf
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/zooyer/dxf/core"
+	"github.com/zooyer/dxf/entities"
+)
+
+// Primitive 展开块参照后得到的图元
+type Primitive struct {
+	Entity    entities.Entity    // 世界坐标下的实体，实现 entities.Transformer 的实体为变换后的副本，否则为原实体
+	Source    entities.Entity    // 文档或块定义中的原实体
+	Transform core.Matrix        // 原实体坐标到世界坐标的变换
+	Parents   []*entities.Insert // 从外到内的块参照链，模型空间中的实体为空
+	Layer     string             // 有效图层，块中 "0" 层上的实体继承块参照的图层
+	Color     int                // 有效 ACI 颜色，已解析随层与随块
+	TrueColor int                // 有效真彩色 0xRRGGBB，0 表示未设置
+}
+
+// Explode 递归展开 Entities 中的块参照，依次产出世界坐标下的图元
+// 块不存在的块参照原样产出；阵列块参照的每个单元分别展开，属性只产出一次
+// 块定义中的 ATTDEF 只产出常量属性；引用自身的块不再展开
+// VisibleOnly 时跳过有效图层关闭或冻结的图元，块中 "0" 层上的实体随块参照的图层一起隐藏
+func (d *Document) Explode() iter.Seq[*Primitive] {
+	return func(yield func(*Primitive) bool) {
+		e := &exploder{doc: d, yield: yield, visiting: make(map[*Block]bool)}
+		top := &Primitive{Layer: "0", Color: 7} // 模型空间中随块的颜色按白色处理
+		for _, ent := range d.Entities {
+			if !e.explode(ent, core.Identity(), top) {
+				return
+			}
+		}
+	}
+}
+
+type exploder struct {
+	doc      *Document
+	yield    func(*Primitive) bool
+	visiting map[*Block]bool // 正在展开的块，用于防止块引用自身
+}
+
+// explode 展开实体，m 为实体坐标到世界坐标的变换，parent 为所在块参照的图元，返回 false 表示停止
+func (e *exploder) explode(ent entities.Entity, m core.Matrix, parent *Primitive) bool {
+	p := e.primitive(ent, m, parent)
+
+	insert, ok := ent.(*entities.Insert)
+	if !ok {
+		return e.emit(p)
+	}
+
+	block := e.doc.Block(insert.BlockName)
+	if block == nil {
+		return e.emit(p)
+	}
+	if e.visiting[block] {
+		return true
+	}
+
+	// 块中实体与属性的父级为当前块参照
+	p.Parents = append(slices.Clip(p.Parents), insert)
+	for _, attr := range insert.Attributes {
+		if !e.emit(e.primitive(attr, m, p)) {
+			return false
+		}
+	}
+
+	e.visiting[block] = true
+	defer delete(e.visiting, block)
+
+	for cell := range insert.Transforms(block.BasePoint) {
+		transform := m.Multiply(cell)
+		for _, sub := range block.Entities {
+			if def, ok := sub.(*entities.Attrib); ok && def.IsDefinition() && !def.Constant() {
+				continue
+			}
+			if !e.explode(sub, transform, p) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// emit 产出图元，有效图层被隐藏时跳过
+func (e *exploder) emit(p *Primitive) bool {
+	if e.doc.Hidden(p.Layer) {
+		return true
+	}
+	return e.yield(p)
+}
+
+// primitive 变换实体并解析有效图层与颜色
+func (e *exploder) primitive(ent entities.Entity, m core.Matrix, parent *Primitive) *Primitive {
+	p := &Primitive{
+		Entity:    ent,
+		Source:    ent,
+		Transform: m,
+		Parents:   parent.Parents,
+		Layer:     ent.Layer(),
+		Color:     ent.Base().Color(),
+		TrueColor: ent.Base().TrueColor(),
+	}
+
+	if t, ok := ent.(entities.Transformer); ok && m != core.Identity() {
+		p.Entity = t.Transformed(m)
+	}
+
+	if p.Layer == "" || (p.Layer == "0" && len(p.Parents) > 0) {
+		p.Layer = parent.Layer
+	}
+
+	switch p.Color {
+	case entities.ColorByBlock:
+		p.Color, p.TrueColor = parent.Color, parent.TrueColor
+	case entities.ColorByLayer:
+		p.Color, p.TrueColor = 7, 0
+		if layer := e.doc.Layer(p.Layer); layer != nil {
+			p.Color, p.TrueColor = layer.Color, layer.TrueColor
+		}
+	}
+
+	return p
+}
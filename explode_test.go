@@ -0,0 +1,157 @@
+package dxf
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/zooyer/dxf/core"
+	"github.com/zooyer/dxf/entities"
+)
+
+func TestDocument_Explode(t *testing.T) {
+	doc := New()
+	doc.Layers["PJ"] = &Layer{Name: "PJ", Color: 3}
+	doc.Layers["WALL"] = &Layer{Name: "WALL", Color: 5}
+
+	// 块 A：0 层随块颜色的直线、WALL 层随层颜色的直线、引用自身的块参照
+	byBlock := entities.NewLine("0", core.Point{X: 1}, core.Point{X: 2})
+	byBlock.Extra = []core.Tag{{Code: 62, Value: "0"}}
+	doc.Blocks["A"] = &Block{Name: "A", Entities: []entities.Entity{
+		byBlock,
+		entities.NewLine("WALL", core.Point{}, core.Point{Y: 1}),
+		entities.NewInsert("0", "A", core.Point{}),
+	}}
+
+	// 块 B：在 (10,0) 处随块颜色插入 A
+	inner := entities.NewInsert("0", "A", core.Point{X: 10})
+	inner.Extra = []core.Tag{{Code: 62, Value: "0"}}
+	doc.Blocks["B"] = &Block{Name: "B", BasePoint: core.Point{X: 10}, Entities: []entities.Entity{inner}}
+
+	// 模型空间：PJ 层、红色、X 方向镜像插入 B
+	insert := entities.NewInsert("PJ", "B", core.Point{X: 100})
+	insert.Scale.X = -1
+	insert.Extra = []core.Tag{{Code: 62, Value: "1"}}
+	doc.Entities = append(doc.Entities, insert)
+
+	var prims []*Primitive
+	for p := range doc.Explode() {
+		prims = append(prims, p)
+	}
+	if len(prims) != 2 {
+		t.Fatalf("期望 2 个图元, 得到 %d", len(prims))
+	}
+
+	p := prims[0]
+	line := p.Entity.(*entities.Line)
+	if p.Source != byBlock || line.Start != (core.Point{X: 99}) || line.End != (core.Point{X: 98}) {
+		t.Errorf("坐标变换不正确: %+v", line)
+	}
+	if p.Layer != "PJ" || p.Color != 1 || len(p.Parents) != 2 || p.Parents[0] != insert {
+		t.Errorf("随块属性不正确: layer %s color %d parents %d", p.Layer, p.Color, len(p.Parents))
+	}
+	if p = prims[1]; p.Layer != "WALL" || p.Color != 5 {
+		t.Errorf("随层属性不正确: layer %s color %d", p.Layer, p.Color)
+	}
+}
+
+func TestDocument_ExplodeVisibleOnly(t *testing.T) {
+	doc := New()
+	doc.VisibleOnly = true
+	doc.Layers["OFF"] = &Layer{Name: "OFF", Off: true}
+	doc.Layers["WALL"] = &Layer{Name: "WALL"}
+
+	// 块中 0 层的直线继承块参照的图层，WALL 层的直线不受影响
+	doc.Blocks["A"] = &Block{Name: "A", Entities: []entities.Entity{
+		entities.NewLine("0", core.Point{}, core.Point{X: 1}),
+		entities.NewLine("WALL", core.Point{}, core.Point{Y: 1}),
+		entities.NewLine("OFF", core.Point{}, core.Point{Z: 1}),
+	}}
+	doc.Entities = append(doc.Entities,
+		entities.NewInsert("OFF", "A", core.Point{}),
+		entities.NewInsert("WALL", "A", core.Point{X: 10}),
+	)
+
+	var layers []string
+	for p := range doc.Explode() {
+		layers = append(layers, p.Layer)
+	}
+	if strings.Join(layers, ",") != "WALL,WALL,WALL" {
+		t.Errorf("隐藏图层上的图元未被跳过: %v", layers)
+	}
+}
+
+func TestDocument_ExplodeExtrusion(t *testing.T) {
+	doc := New()
+	doc.Blocks["A"] = &Block{Name: "A", Entities: []entities.Entity{
+		entities.NewLine("0", core.Point{X: 1}, core.Point{X: 2}),
+	}}
+
+	// 拉伸方向 (0,0,-1) 的插入，OCS 的 X 轴指向世界 -X
+	insert := entities.NewInsert("0", "A", core.Point{X: 5})
+	insert.Extrusion = core.Point{Z: -1}
+	doc.Entities = append(doc.Entities, insert)
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var prims []*Primitive
+	for p := range read.Explode() {
+		prims = append(prims, p)
+	}
+	if len(prims) != 1 {
+		t.Fatalf("期望 1 个图元, 得到 %d", len(prims))
+	}
+	if line := prims[0].Entity.(*entities.Line); line.Start != (core.Point{X: -6}) || line.End != (core.Point{X: -7}) {
+		t.Errorf("拉伸方向未生效: %+v", line)
+	}
+}
+
+func TestDocument_ExplodeMInsert(t *testing.T) {
+	data := "0\nSECTION\n2\nBLOCKS\n" +
+		"0\nBLOCK\n2\nW\n10\n0\n20\n0\n30\n0\n" +
+		"0\nLINE\n8\n0\n10\n0\n20\n0\n11\n1\n21\n0\n" +
+		"0\nENDBLK\n0\nENDSEC\n" +
+		"0\nSECTION\n2\nENTITIES\n" +
+		"0\nMINSERT\n8\nPJ\n100\nAcDbMInsertBlock\n2\nW\n10\n100\n20\n0\n41\n2\n50\n90\n70\n3\n71\n2\n44\n10\n45\n20\n" +
+		"0\nENDSEC\n0\nEOF\n"
+
+	doc, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	insert := doc.Entities[0].(*entities.Insert)
+	if insert.Columns != 3 || insert.Rows != 2 || insert.ColumnSpacing != 10 || insert.RowSpacing != 20 {
+		t.Fatalf("阵列参数不正确: %+v", insert)
+	}
+
+	var starts []core.Point
+	for p := range doc.Explode() {
+		starts = append(starts, p.Entity.(*entities.Line).Start)
+	}
+	if len(starts) != 6 {
+		t.Fatalf("期望 6 个图元, 得到 %d", len(starts))
+	}
+
+	// 旋转 90° 后列沿 +Y、行沿 -X 排列，间距不受缩放影响
+	for i, want := range []core.Point{{X: 100}, {X: 100, Y: 10}, {X: 100, Y: 20}, {X: 80}, {X: 80, Y: 10}, {X: 80, Y: 20}} {
+		if math.Abs(starts[i].X-want.X) > 1e-9 || math.Abs(starts[i].Y-want.Y) > 1e-9 {
+			t.Errorf("单元 %d 期望 %+v, 得到 %+v", i, want, starts[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err = doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.Contains(out, "0\nINSERT\n") || strings.Contains(out, "MINSERT") || !strings.Contains(out, "AcDbMInsertBlock") {
+		t.Errorf("MINSERT 写出不正确")
+	}
+}
@@ -5,6 +5,7 @@ go 1.24.5
 require (
 	github.com/ncruces/zenity v0.10.14
 	github.com/zooyer/golib v1.0.4
+	golang.org/x/text v0.28.0
 )
 
 require (
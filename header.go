@@ -0,0 +1,151 @@
+package dxf
+
+import (
+	"strings"
+
+	"github.com/zooyer/dxf/core"
+)
+
+// Header 对应 HEADER 段，按出现顺序保存所有系统变量
+type Header struct {
+	Vars  map[string][]core.Tag // 变量名 (如 "$ACADVER") → 变量值标签
+	Names []string              // 变量出现的顺序
+}
+
+func newHeader() *Header {
+	return &Header{Vars: make(map[string][]core.Tag)}
+}
+
+// Get 返回变量的原始标签，变量名大小写不敏感，可省略 "$"
+func (h *Header) Get(name string) []core.Tag {
+	if h == nil {
+		return nil
+	}
+	return h.Vars[headerName(name)]
+}
+
+// Set 设置变量值，新变量追加到末尾
+func (h *Header) Set(name string, tags ...core.Tag) {
+	name = headerName(name)
+	if _, ok := h.Vars[name]; !ok {
+		h.Names = append(h.Names, name)
+	}
+	h.Vars[name] = tags
+}
+
+// Has 判断变量是否存在
+func (h *Header) Has(name string) bool {
+	return len(h.Get(name)) > 0
+}
+
+// String 返回变量的第一个值 (字符串)
+func (h *Header) String(name string) string {
+	if tags := h.Get(name); len(tags) > 0 {
+		return tags[0].AsString()
+	}
+	return ""
+}
+
+// Int 返回变量的第一个值 (整数)，不存在时返回 def
+func (h *Header) Int(name string, def int) int {
+	if tags := h.Get(name); len(tags) > 0 {
+		return tags[0].AsInt()
+	}
+	return def
+}
+
+// Float 返回变量的第一个值 (浮点数)，不存在时返回 def
+func (h *Header) Float(name string, def float64) float64 {
+	if tags := h.Get(name); len(tags) > 0 {
+		return tags[0].AsFloat()
+	}
+	return def
+}
+
+// Point 返回坐标类变量 (组码 10/20/30)
+func (h *Header) Point(name string) core.Point {
+	var p core.Point
+	for _, tag := range h.Get(name) {
+		switch tag.Code {
+		case 10:
+			p.X = tag.AsFloat()
+		case 20:
+			p.Y = tag.AsFloat()
+		case 30:
+			p.Z = tag.AsFloat()
+		}
+	}
+	return p
+}
+
+// Version 图纸版本 $ACADVER，如 "AC1015" (R2000)
+func (h *Header) Version() string {
+	return h.String("$ACADVER")
+}
+
+// InsUnits 插入单位 $INSUNITS，0 无单位、1 英寸、4 毫米、5 厘米、6 米
+func (h *Header) InsUnits() int {
+	return h.Int("$INSUNITS", 0)
+}
+
+// CodePage 图纸代码页 $DWGCODEPAGE，如 "ANSI_936"
+func (h *Header) CodePage() string {
+	return h.String("$DWGCODEPAGE")
+}
+
+// ExtMin 图形范围左下角 $EXTMIN
+func (h *Header) ExtMin() core.Point {
+	return h.Point("$EXTMIN")
+}
+
+// ExtMax 图形范围右上角 $EXTMAX
+func (h *Header) ExtMax() core.Point {
+	return h.Point("$EXTMAX")
+}
+
+// Extents 图形范围 $EXTMIN ~ $EXTMAX
+func (h *Header) Extents() core.BBox {
+	return core.BBox{Min: h.ExtMin(), Max: h.ExtMax()}
+}
+
+// LUnits 长度单位格式 $LUNITS，2 为十进制
+func (h *Header) LUnits() int {
+	return h.Int("$LUNITS", 2)
+}
+
+// LUPrec 长度单位精度 $LUPREC
+func (h *Header) LUPrec() int {
+	return h.Int("$LUPREC", 4)
+}
+
+// DimScale 全局标注比例 $DIMSCALE
+func (h *Header) DimScale() float64 {
+	return h.Float("$DIMSCALE", 1.0)
+}
+
+// headerName 统一变量名格式：大写并带 "$" 前缀
+func headerName(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if !strings.HasPrefix(name, "$") {
+		name = "$" + name
+	}
+	return name
+}
+
+func (d *Document) parseHeader(scanner *core.Scanner) {
+	var name string
+	for scanner.Next() {
+		tag := scanner.LastTag
+		if tag.Code == 0 && strings.ToUpper(tag.Value) == "ENDSEC" {
+			break
+		}
+		if tag.Code == 9 {
+			name = tag.AsString()
+			d.Header.Set(name)
+			continue
+		}
+		if name != "" {
+			d.Header.Set(name, append(d.Header.Get(name), tag)...)
+		}
+	}
+}
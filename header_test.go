@@ -0,0 +1,47 @@
+package dxf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoad_Header(t *testing.T) {
+	data := "0\nSECTION\n2\nHEADER\n" +
+		"9\n$ACADVER\n1\nAC1015\n" +
+		"9\n$DWGCODEPAGE\n3\nANSI_936\n" +
+		"9\n$INSUNITS\n70\n4\n" +
+		"9\n$EXTMIN\n10\n-1.5\n20\n2\n30\n0\n" +
+		"9\n$EXTMAX\n10\n100\n20\n200\n30\n0\n" +
+		"0\nENDSEC\n0\nEOF\n"
+
+	doc, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := doc.Header
+	if h.Version() != "AC1015" {
+		t.Errorf("$ACADVER: 得到 %q", h.Version())
+	}
+	if h.CodePage() != "ANSI_936" {
+		t.Errorf("$DWGCODEPAGE: 得到 %q", h.CodePage())
+	}
+	if h.InsUnits() != 4 {
+		t.Errorf("$INSUNITS: 得到 %d", h.InsUnits())
+	}
+	if p := h.ExtMin(); p.X != -1.5 || p.Y != 2 {
+		t.Errorf("$EXTMIN: 得到 %+v", p)
+	}
+	if p := h.ExtMax(); p.X != 100 || p.Y != 200 {
+		t.Errorf("$EXTMAX: 得到 %+v", p)
+	}
+	if h.DimScale() != 1 {
+		t.Errorf("$DIMSCALE 默认值: 得到 %v", h.DimScale())
+	}
+	if len(h.Get("extmin")) != 3 {
+		t.Errorf("变量名应大小写不敏感: 得到 %v", h.Get("extmin"))
+	}
+	if len(h.Names) != 5 || h.Names[0] != "$ACADVER" {
+		t.Errorf("变量顺序: 得到 %v", h.Names)
+	}
+}
@@ -0,0 +1,63 @@
+package dxf
+
+import (
+	"strings"
+
+	"github.com/zooyer/dxf/core"
+	"github.com/zooyer/dxf/entities"
+)
+
+// Entity 按句柄查找实体 (含块内实体与块参照的属性)，不存在时返回 nil
+func (d *Document) Entity(handle string) entities.Entity {
+	if handle == "" {
+		return nil
+	}
+
+	find := func(list []entities.Entity) entities.Entity {
+		for _, e := range list {
+			if strings.EqualFold(e.Base().Handle, handle) {
+				return e
+			}
+			if insert, ok := e.(*entities.Insert); ok {
+				for _, attr := range insert.Attributes {
+					if strings.EqualFold(attr.Handle, handle) {
+						return attr
+					}
+				}
+			}
+		}
+		return nil
+	}
+
+	if e := find(d.Entities); e != nil {
+		return e
+	}
+	for _, block := range d.Blocks {
+		if e := find(block.Entities); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// Note 返回引线的箭头位置与注释文字 (已去除格式代码)，不是引线或没有顶点时 ok 为 false
+// LEADER 的文字来自 340 关联的 MTEXT，MULTILEADER 的文字来自自身的多行文字内容
+func (d *Document) Note(leader entities.Entity) (tip core.Point, text string, ok bool) {
+	switch l := leader.(type) {
+	case *entities.Leader:
+		if tip, ok = l.ArrowTip(); !ok {
+			return
+		}
+		switch annotation := d.Entity(l.Annotation).(type) {
+		case *entities.MText:
+			text = annotation.PlainText()
+		case *entities.Text:
+			text = annotation.PlainText()
+		}
+	case *entities.MLeader:
+		if tip, ok = l.ArrowTip(); ok {
+			text = l.PlainText()
+		}
+	}
+	return
+}
@@ -0,0 +1,109 @@
+package dxf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zooyer/dxf/core"
+	"github.com/zooyer/dxf/entities"
+)
+
+func TestDocument_Note(t *testing.T) {
+	data := "0\nSECTION\n2\nENTITIES\n" +
+		"0\nLEADER\n5\n70\n8\nNOTE\n100\nAcDbLeader\n3\nSTANDARD\n71\n1\n72\n0\n73\n0\n74\n0\n75\n0\n40\n2.5\n41\n20\n76\n2\n" +
+		"10\n100\n20\n50\n30\n0\n10\n120\n20\n60\n30\n0\n340\n71\n" +
+		"0\nMTEXT\n5\n71\n8\nNOTE\n10\n121\n20\n61\n40\n2.5\n1\n{\\fSimSun;钢化玻璃}\n" +
+		"0\nMULTILEADER\n5\n72\n8\nNOTE\n100\nAcDbMLeader\n270\n2\n" +
+		"300\nCONTEXT_DATA{\n40\n1\n10\n210\n20\n160\n30\n0\n41\n3\n140\n1\n145\n1\n174\n1\n175\n1\n176\n0\n177\n0\n" +
+		"290\n1\n304\n内开\\P窗\n11\n0\n21\n0\n31\n1\n12\n210\n22\n160\n32\n0\n13\n1\n23\n0\n33\n0\n42\n0\n43\n0\n171\n1\n" +
+		"296\n0\n110\n0\n120\n0\n130\n0\n111\n1\n121\n0\n131\n0\n112\n0\n122\n1\n132\n0\n297\n0\n" +
+		"302\nLEADER{\n290\n1\n291\n1\n10\n208\n20\n158\n30\n0\n11\n1\n21\n0\n31\n0\n90\n0\n40\n2\n" +
+		"304\nLEADER_LINE{\n10\n200\n20\n150\n30\n0\n91\n0\n305\n}\n" +
+		"304\nLEADER_LINE{\n10\n205\n20\n140\n30\n0\n91\n1\n305\n}\n" +
+		"271\n0\n303\n}\n272\n9\n273\n9\n301\n}\n" +
+		"340\n1A\n90\n0\n170\n1\n91\n-1056964608\n171\n-2\n290\n1\n291\n1\n41\n2\n42\n4\n172\n2\n" +
+		"0\nENDSEC\n0\nEOF\n"
+
+	doc, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Entities) != 3 {
+		t.Fatalf("期望 3 个实体, 得到 %d", len(doc.Entities))
+	}
+
+	tip, text, ok := doc.Note(doc.Entities[0])
+	if !ok || tip != (core.Point{X: 100, Y: 50}) || text != "钢化玻璃" {
+		t.Errorf("LEADER 注释不正确: %+v %q %v", tip, text, ok)
+	}
+
+	ml := doc.Entities[2].(*entities.MLeader)
+	if len(ml.Branches) != 1 || len(ml.Branches[0].Lines) != 2 || ml.Branches[0].Landing != (core.Point{X: 208, Y: 158}) {
+		t.Fatalf("MULTILEADER 引线不正确: %+v", ml.Branches)
+	}
+	if tips := ml.ArrowTips(); len(tips) != 2 || tips[1] != (core.Point{X: 205, Y: 140}) {
+		t.Errorf("箭头位置不正确: %+v", tips)
+	}
+	tip, text, ok = doc.Note(ml)
+	if !ok || tip != (core.Point{X: 200, Y: 150}) || text != "内开\n窗" {
+		t.Errorf("MULTILEADER 注释不正确: %+v %q %v", tip, text, ok)
+	}
+	if box := ml.BBox(); box.Min != (core.Point{X: 200, Y: 140}) || box.Max.X <= 210 {
+		t.Errorf("MULTILEADER 包围盒不正确: %+v", box)
+	}
+
+	// 多重引线原样写出
+	var buf bytes.Buffer
+	if _, err = doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "LEADER_LINE{\n 10\n205") {
+		t.Errorf("MULTILEADER 写出不完整")
+	}
+}
+
+func TestDocument_ExplodeMLeader(t *testing.T) {
+	data := "0\nSECTION\n2\nENTITIES\n" +
+		"0\nMULTILEADER\n5\n72\n8\nNOTE\n100\nAcDbMLeader\n270\n2\n" +
+		"300\nCONTEXT_DATA{\n40\n1\n10\n10\n20\n5\n30\n0\n41\n2.5\n140\n1\n" +
+		"290\n1\n304\n窗\n11\n0\n21\n0\n31\n1\n12\n10\n22\n5\n32\n0\n13\n1\n23\n0\n33\n0\n43\n0\n171\n1\n" +
+		"302\nLEADER{\n10\n8\n20\n5\n30\n0\n11\n1\n21\n0\n31\n0\n40\n2\n" +
+		"304\nLEADER_LINE{\n10\n0\n20\n0\n30\n0\n305\n}\n303\n}\n301\n}\n172\n2\n" +
+		"0\nENDSEC\n0\nEOF\n"
+
+	doc, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 多重引线放入块中，在 (100,0) 处旋转 90° 并放大 2 倍插入
+	doc.Blocks["NOTE"] = &Block{Name: "NOTE", Entities: doc.Entities}
+	insert := entities.NewInsert("0", "NOTE", core.Point{X: 100})
+	insert.Rotation, insert.Scale = 90, core.Point{X: 2, Y: 2, Z: 1}
+	doc.Entities = []entities.Entity{insert}
+
+	var ml *entities.MLeader
+	for p := range doc.Explode() {
+		ml, _ = p.Entity.(*entities.MLeader)
+	}
+	if ml == nil {
+		t.Fatal("未展开多重引线")
+	}
+
+	near := func(a, b core.Point) bool { return a.Sub(b).Length() < 1e-9 }
+	if tip, ok := ml.ArrowTip(); !ok || !near(tip, core.Point{X: 100}) {
+		t.Errorf("箭头位置不正确: %+v", tip)
+	}
+	b := ml.Branches[0]
+	if !near(b.Landing, core.Point{X: 90, Y: 16}) || !near(b.Dogleg, core.Point{Y: 1}) || b.DoglegLength != 4 {
+		t.Errorf("连接点不正确: %+v", b)
+	}
+	if !near(ml.BasePoint, core.Point{X: 90, Y: 20}) || !near(ml.TextLocation, core.Point{X: 90, Y: 20}) ||
+		!near(ml.TextDirection, core.Point{Y: 1}) || ml.TextHeight != 5 {
+		t.Errorf("内容位置不正确: %+v", ml)
+	}
+	if ml.PlainText() != "窗" {
+		t.Errorf("文字不正确: %q", ml.PlainText())
+	}
+}
@@ -0,0 +1,60 @@
+package dxf
+
+import (
+	"golang.org/x/text/encoding"
+
+	"github.com/zooyer/dxf/core"
+)
+
+// Option 配置 Load/Open 的行为
+type Option func(*options)
+
+type options struct {
+	encoding encoding.Encoding // 强制使用的字符编码，nil 表示按 $DWGCODEPAGE 自动识别
+	strict   bool              // 严格模式，数值无法解析时终止加载
+	progress ProgressFunc      // 加载进度回调
+	visible  bool              // 跳过关闭或冻结图层上的实体
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithEncoding 强制使用指定编码解码字符串，忽略图纸中的 $DWGCODEPAGE
+func WithEncoding(enc encoding.Encoding) Option {
+	return func(o *options) {
+		o.encoding = enc
+	}
+}
+
+// WithCodePage 按 DXF 代码页名称 (如 "ANSI_936") 强制指定编码
+func WithCodePage(codePage string) Option {
+	return WithEncoding(core.CodePageEncoding(codePage))
+}
+
+// WithStrict 开启严格模式：数值无法解析时立即返回 *core.ParseError
+// 默认的宽松模式会继续解析，并把错误记录到 Document.Warnings
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithVisibleOnly 跳过 ENTITIES 段中位于关闭或冻结图层上的实体
+// 块定义照常读取，Explode 与 utils.BBoxCache 展开块参照时按有效图层跳过 (参见 Document.Hidden)
+func WithVisibleOnly() Option {
+	return func(o *options) {
+		o.visible = true
+	}
+}
+
+// WithProgress 设置加载进度回调，报告已读取的字节数、总大小与当前所在的段
+func WithProgress(fn ProgressFunc) Option {
+	return func(o *options) {
+		o.progress = fn
+	}
+}
@@ -0,0 +1,78 @@
+package dxf
+
+import (
+	"context"
+	"io"
+	"os"
+)
+
+// progressStep 两次进度回调之间至少读取的字节数
+const progressStep = 256 << 10
+
+// Progress 加载进度
+type Progress struct {
+	Read    int64  // 已读取的字节数
+	Total   int64  // 总字节数，未知时为 0
+	Section string // 当前所在的段，如 "ENTITIES"
+}
+
+// ProgressFunc 接收加载进度，在读取数据的协程中同步调用
+type ProgressFunc func(progress Progress)
+
+// progressReader 在读取时检查 context 是否取消，并按步长报告进度
+type progressReader struct {
+	ctx      context.Context
+	reader   io.Reader
+	fn       ProgressFunc
+	progress Progress
+	reported int64 // 上一次报告时已读取的字节数
+}
+
+func newProgressReader(ctx context.Context, reader io.Reader, fn ProgressFunc) *progressReader {
+	return &progressReader{
+		ctx:      ctx,
+		reader:   reader,
+		fn:       fn,
+		progress: Progress{Total: readerSize(reader)},
+	}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	if err := p.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := p.reader.Read(b)
+	p.progress.Read += int64(n)
+	if p.progress.Read-p.reported >= progressStep || err == io.EOF {
+		p.report()
+	}
+
+	return n, err
+}
+
+// section 记录当前所在的段并报告进度
+func (p *progressReader) section(name string) {
+	p.progress.Section = name
+	p.report()
+}
+
+func (p *progressReader) report() {
+	p.reported = p.progress.Read
+	if p.fn != nil {
+		p.fn(p.progress)
+	}
+}
+
+// readerSize 尽量获取数据的总大小，未知时返回 0
+func readerSize(reader io.Reader) int64 {
+	switch r := reader.(type) {
+	case interface{ Size() int64 }: // bytes.Reader、strings.Reader
+		return r.Size()
+	case interface{ Stat() (os.FileInfo, error) }: // os.File
+		if info, err := r.Stat(); err == nil && info.Mode().IsRegular() {
+			return info.Size()
+		}
+	}
+	return 0
+}
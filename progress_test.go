@@ -0,0 +1,50 @@
+package dxf
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoadContext(t *testing.T) {
+	data := "0\nSECTION\n2\nHEADER\n9\n$ACADVER\n1\nAC1015\n0\nENDSEC\n" +
+		"0\nSECTION\n2\nENTITIES\n" +
+		strings.Repeat("0\nLINE\n8\n0\n10\n0\n20\n0\n11\n1\n21\n1\n", 50000) +
+		"0\nENDSEC\n0\nEOF\n"
+
+	var (
+		last     Progress
+		sections []string
+	)
+	doc, err := LoadContext(context.Background(), strings.NewReader(data), WithProgress(func(p Progress) {
+		if p.Section != last.Section {
+			sections = append(sections, p.Section)
+		}
+		last = p
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Entities) != 50000 {
+		t.Errorf("期望 50000 个实体, 得到 %d", len(doc.Entities))
+	}
+	if last.Read != int64(len(data)) || last.Total != int64(len(data)) {
+		t.Errorf("进度不正确: %+v", last)
+	}
+	if strings.Join(sections, ",") != "HEADER,ENTITIES" {
+		t.Errorf("段进度不正确: %v", sections)
+	}
+
+	// 读取到一半时取消
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err = LoadContext(ctx, strings.NewReader(data), WithProgress(func(p Progress) {
+		if p.Read > p.Total/2 {
+			cancel()
+		}
+	}))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("期望 context.Canceled, 得到 %v", err)
+	}
+}
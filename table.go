@@ -0,0 +1,204 @@
+package dxf
+
+import (
+	"strings"
+
+	"github.com/zooyer/dxf/core"
+	"github.com/zooyer/dxf/entities"
+)
+
+type DimStyle struct {
+	Name      string
+	Handle    string     // 对应组码 105，样式记录的句柄
+	Flags     int        // 对应组码 70，标准标志
+	Precision int        // 对应组码 271 DIMDEC，显示的小数位数
+	ExLimit   float64    // 对应组码 44 DIMEXE，标注线超出延伸线的长度
+	Scale     float64    // 对应组码 40 DIMSCALE，全局比例，影响所有标注特征)
+	Extra     []core.Tag // 未解析的标注变量，写出时原样输出
+}
+
+// Layer 图层 (LAYER 表记录)
+type Layer struct {
+	Name       string
+	Handle     string     // 组码 5
+	Flags      int        // 组码 70 原值 (含冻结、锁定以外的标志位)
+	Frozen     bool       // 组码 70 第 1 位，冻结
+	Locked     bool       // 组码 70 第 4 位，锁定
+	Off        bool       // 组码 62 为负数时图层关闭
+	Color      int        // 组码 62，ACI 颜色号 (取绝对值)
+	TrueColor  int        // 组码 420，真彩色 0xRRGGBB，0 表示未设置
+	LineType   string     // 组码 6，线型名称
+	LineWeight int        // 组码 370，线宽 (1/100 毫米)，-3 表示默认
+	Plot       bool       // 组码 290，是否打印
+	Extra      []core.Tag // 未解析的组码 (如 102 组、打印样式)，写出时原样输出
+}
+
+// Visible 图层是否可见 (未关闭且未冻结)
+func (l *Layer) Visible() bool {
+	return !l.Off && !l.Frozen
+}
+
+// newLayer 创建默认属性的图层：白色、实线、默认线宽、可打印
+func newLayer(name string) *Layer {
+	return &Layer{
+		Name:       name,
+		Color:      7,
+		LineType:   "Continuous",
+		LineWeight: -3,
+		Plot:       true,
+	}
+}
+
+// Table 符号表 (TABLE ... ENDTAB)，原样保留表头与记录，写出时在此基础上补充缺少的记录
+type Table struct {
+	Name    string
+	Tags    []core.Tag            // 表头组码 (句柄、子类标记、记录数等)，不含表名
+	Records []*entities.RawEntity // 表记录，如 LTYPE、STYLE、BLOCK_RECORD
+}
+
+// Handle 返回符号表的句柄
+func (t *Table) Handle() string {
+	for _, tag := range t.Tags {
+		if tag.Code == 5 {
+			return tag.Value
+		}
+	}
+	return ""
+}
+
+// Record 按名称 (组码 2) 查找表记录，大小写不敏感
+func (t *Table) Record(name string) *entities.RawEntity {
+	for _, rec := range t.Records {
+		if strings.EqualFold(rec.Value(2), name) {
+			return rec
+		}
+	}
+	return nil
+}
+
+func (d *Document) parseTables(scanner *core.Scanner) {
+	var currentTable *Table
+
+	defer func() {
+		d.parseLayers()
+		d.parseDimStyles()
+	}()
+
+	for scanner.Next() {
+		tag := scanner.LastTag
+		if tag.Code != 0 {
+			continue
+		}
+
+		switch strings.ToUpper(tag.Value) {
+		case "ENDSEC":
+			return
+		case "TABLE":
+			currentTable = &Table{}
+			for _, t := range scanner.ReadEntity() {
+				if t.Code == 2 && currentTable.Name == "" {
+					currentTable.Name = strings.ToUpper(t.Value)
+					continue
+				}
+				currentTable.Tags = append(currentTable.Tags, t)
+			}
+			d.Tables[currentTable.Name] = currentTable
+		case "ENDTAB":
+			scanner.ReadEntity()
+			currentTable = nil
+		default:
+			record := entities.NewRawEntity(tag.Value)
+			_ = record.Parse(scanner)
+			if currentTable != nil {
+				currentTable.Records = append(currentTable.Records, record)
+			}
+		}
+	}
+}
+
+// parseLayers 从 LAYER 表记录中解析图层
+func (d *Document) parseLayers() {
+	table, ok := d.Tables["LAYER"]
+	if !ok {
+		return
+	}
+
+	for _, record := range table.Records {
+		layer := newLayer("")
+
+		for _, t := range record.Tags {
+			switch t.Code {
+			case 2:
+				layer.Name = t.Value
+			case 5:
+				layer.Handle = t.Value
+			case 70:
+				layer.Flags = t.AsInt()
+				layer.Frozen = layer.Flags&1 != 0
+				layer.Locked = layer.Flags&4 != 0
+			case 62:
+				layer.Color = t.AsInt()
+				if layer.Color < 0 {
+					layer.Off, layer.Color = true, -layer.Color
+				}
+			case 420:
+				layer.TrueColor = t.AsInt()
+			case 6:
+				layer.LineType = t.Value
+			case 370:
+				layer.LineWeight = t.AsInt()
+			case 290:
+				layer.Plot = t.AsInt() != 0
+			case 100, 330:
+				// 子类标记与所属表由写出时重新生成
+			default:
+				layer.Extra = append(layer.Extra, t)
+			}
+		}
+
+		if layer.Name != "" {
+			d.Layers[strings.ToUpper(layer.Name)] = layer
+		}
+	}
+}
+
+// parseDimStyles 从 DIMSTYLE 表记录中解析标注样式
+func (d *Document) parseDimStyles() {
+	table, ok := d.Tables["DIMSTYLE"]
+	if !ok {
+		return
+	}
+
+	for _, record := range table.Records {
+		currentStyle := &DimStyle{
+			Precision: 0,
+			ExLimit:   0.0,
+			Scale:     1.0, // 默认为 1.0，防止乘法归零
+		}
+
+		for _, t := range record.Tags {
+			switch t.Code {
+			case 2: // 样式名称
+				currentStyle.Name = t.Value
+			case 105: // 句柄
+				currentStyle.Handle = t.Value
+			case 70: // 标志
+				currentStyle.Flags = t.AsInt()
+			case 271: // 精度
+				currentStyle.Precision = t.AsInt()
+			case 44: // 标注线超出延伸线长度 (DIMEXE)
+				currentStyle.ExLimit = t.AsFloat()
+			case 40: // 全局标注比例 (DIMSCALE)
+				currentStyle.Scale = t.AsFloat()
+			case 100, 330:
+				// 子类标记与所属表由写出时重新生成
+			default:
+				currentStyle.Extra = append(currentStyle.Extra, t)
+			}
+		}
+
+		if currentStyle.Name != "" {
+			d.DimStyles[strings.ToUpper(currentStyle.Name)] = currentStyle
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package dxf
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad_Layers(t *testing.T) {
+	data := "0\nSECTION\n2\nTABLES\n" +
+		"0\nTABLE\n2\nLAYER\n5\n2\n70\n3\n" +
+		"0\nLAYER\n5\n10\n102\n{ACAD_XDICTIONARY\n360\n99\n102\n}\n100\nAcDbSymbolTableRecord\n100\nAcDbLayerTableRecord\n2\n0\n70\n0\n62\n7\n6\nContinuous\n370\n-3\n390\nF\n" +
+		"0\nLAYER\n5\n11\n100\nAcDbSymbolTableRecord\n100\nAcDbLayerTableRecord\n2\nPJ\n70\n5\n62\n1\n420\n16711680\n6\nDASHED\n290\n0\n370\n25\n" +
+		"0\nLAYER\n5\n12\n100\nAcDbSymbolTableRecord\n100\nAcDbLayerTableRecord\n2\nBZ\n70\n0\n62\n-3\n6\nContinuous\n" +
+		"0\nENDTAB\n0\nENDSEC\n" +
+		"0\nSECTION\n2\nENTITIES\n" +
+		"0\nLINE\n5\n20\n8\nPJ\n0\nLINE\n5\n21\n8\nBZ\n0\nLINE\n5\n22\n8\nNEW\n" +
+		"0\nENDSEC\n0\nEOF\n"
+
+	doc, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pj := doc.Layer("pj")
+	if pj == nil || !pj.Frozen || !pj.Locked || pj.Off || pj.Color != 1 || pj.TrueColor != 0xFF0000 ||
+		pj.LineType != "DASHED" || pj.LineWeight != 25 || pj.Plot {
+		t.Errorf("PJ 图层不正确: %+v", pj)
+	}
+	if bz := doc.Layer("BZ"); bz == nil || !bz.Off || bz.Color != 3 || bz.Visible() || !bz.Plot {
+		t.Errorf("BZ 图层不正确: %+v", bz)
+	}
+
+	// 写出后图层属性保持不变，未定义的图层被补充
+	var buf bytes.Buffer
+	if _, err = doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "  5\n10\n102\n{ACAD_XDICTIONARY\n360\n99\n102\n}\n330\n") {
+		t.Error("102 组应写在句柄之后")
+	}
+	again, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again.Layer("PJ"), pj) || again.Layer("NEW") == nil || !again.Layer("BZ").Off {
+		t.Errorf("写出后图层不一致: %+v", again.Layer("PJ"))
+	}
+
+	// 跳过关闭或冻结图层上的实体
+	doc, err = Load(strings.NewReader(data), WithVisibleOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Entities) != 1 || doc.Entities[0].Layer() != "NEW" {
+		t.Errorf("期望只保留 NEW 图层上的实体, 得到 %d 个", len(doc.Entities))
+	}
+}
@@ -1,13 +1,17 @@
 package utils
 
 import (
+	"strings"
+
+	"github.com/zooyer/dxf"
 	"github.com/zooyer/dxf/entities"
 )
 
+// GetAttrs 返回块参照的属性值，键为去除首尾空格的属性标签，值为原值
 func GetAttrs(ins *entities.Insert) map[string]string {
 	var attrs = make(map[string]string)
 	for _, a := range ins.Attributes {
-		attrs[a.Tag] = a.Text
+		attrs[strings.TrimSpace(a.Tag)] = a.Text
 	}
 
 	return attrs
@@ -16,3 +20,61 @@ func GetAttrs(ins *entities.Insert) map[string]string {
 func GetAttr(ins *entities.Insert, key string) string {
 	return GetAttrs(ins)[key]
 }
+
+// ResolveAttribs 合并块定义中的 ATTDEF 与块参照的 ATTRIB，按 ATTDEF 的顺序返回世界坐标下的属性
+// 块参照中有同名 (大小写与首尾空格不敏感) ATTRIB 的使用 ATTRIB；常量属性与缺少的属性使用 ATTDEF 的默认值，
+// 转换为插入位置的 ATTRIB；没有对应 ATTDEF 的 ATTRIB 追加在最后
+func ResolveAttribs(d *dxf.Document, ins *entities.Insert) []*entities.Attrib {
+	block := d.Block(ins.BlockName)
+	if block == nil {
+		return ins.Attributes
+	}
+
+	values := make(map[string]*entities.Attrib)
+	for _, a := range ins.Attributes {
+		values[attrKey(a)] = a
+	}
+
+	var (
+		result    []*entities.Attrib
+		transform = ins.Transform(block.BasePoint)
+	)
+	for _, def := range block.AttDefs() {
+		key := attrKey(def)
+		if a, ok := values[key]; ok && !def.Constant() {
+			result = append(result, a)
+			delete(values, key)
+			continue
+		}
+
+		a := def.Transformed(transform).(*entities.Attrib)
+		a.TypeName, a.Prompt, a.Handle, a.Owner = "ATTRIB", "", "", ins.Handle
+		if ins.LayerName != "" && a.LayerName == "0" {
+			a.LayerName = ins.LayerName
+		}
+		result = append(result, a)
+		delete(values, key)
+	}
+
+	for _, a := range ins.Attributes {
+		if _, ok := values[attrKey(a)]; ok {
+			result = append(result, a)
+		}
+	}
+
+	return result
+}
+
+// ResolveAttrs 返回合并 ATTDEF 默认值后的属性值 (已去除格式代码)，键为去除首尾空格的属性标签
+func ResolveAttrs(d *dxf.Document, ins *entities.Insert) map[string]string {
+	attrs := make(map[string]string)
+	for _, a := range ResolveAttribs(d, ins) {
+		attrs[strings.TrimSpace(a.Tag)] = a.Value()
+	}
+	return attrs
+}
+
+// attrKey 匹配 ATTDEF 与 ATTRIB 时使用的键，忽略大小写与首尾空格
+func attrKey(a *entities.Attrib) string {
+	return strings.ToUpper(strings.TrimSpace(a.Tag))
+}
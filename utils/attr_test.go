@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zooyer/dxf"
+	"github.com/zooyer/dxf/core"
+	"github.com/zooyer/dxf/entities"
+)
+
+func TestResolveAttrs(t *testing.T) {
+	data := "0\nSECTION\n2\nBLOCKS\n" +
+		"0\nBLOCK\n2\nC1\n10\n0\n20\n0\n30\n0\n" +
+		"0\nATTDEF\n8\n0\n100\nAcDbText\n10\n0\n20\n0\n40\n2.5\n1\nC\n100\nAcDbAttributeDefinition\n3\n类型\n2\n类型\n70\n3\n" +
+		"0\nATTDEF\n8\n0\n100\nAcDbText\n10\n0\n20\n-5\n40\n2.5\n1\n1.2%%d\n50\n90\n100\nAcDbAttributeDefinition\n3\n面积\n2\n面积\n70\n8\n280\n0\n280\n1\n" +
+		"0\nATTDEF\n8\n0\n100\nAcDbText\n10\n0\n20\n-10\n40\n2.5\n1\n备注\n100\nAcDbAttributeDefinition\n3\n备注\n2\n备注\n70\n0\n" +
+		"101\nEmbedded Object\n10\n0\n20\n-10\n40\n2.5\n41\n20\n71\n1\n72\n5\n1\n第一行\\P第二行\n" +
+		"0\nENDBLK\n0\nENDSEC\n" +
+		"0\nSECTION\n2\nENTITIES\n" +
+		"0\nINSERT\n5\n80\n8\nPJ\n66\n1\n2\nC1\n10\n100\n20\n0\n" +
+		"0\nATTRIB\n5\n81\n8\n0\n100\nAcDbText\n10\n100\n20\n-10\n40\n2.5\n1\n窗户\n100\nAcDbAttribute\n2\n 备注 \n70\n0\n" +
+		"0\nATTRIB\n5\n82\n8\n0\n100\nAcDbText\n10\n100\n20\n-20\n40\n2.5\n1\nA\n100\nAcDbAttribute\n2\n类型\n70\n0\n" +
+		"0\nSEQEND\n5\n83\n0\nENDSEC\n0\nEOF\n"
+
+	doc, err := dxf.Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defs := doc.Block("C1").AttDefs()
+	if len(defs) != 3 || !defs[0].Constant() || !defs[0].Invisible() || !defs[1].Preset() || !defs[1].LockPosition {
+		t.Fatalf("ATTDEF 标志不正确: %+v", defs)
+	}
+	if !defs[2].Multiline() || defs[2].Value() != "第一行\n第二行" || defs[2].MText.Width != 20 {
+		t.Errorf("多行属性不正确: %+v", defs[2].MText)
+	}
+
+	ins := doc.Entities[0].(*entities.Insert)
+	attrs := ResolveAttribs(doc, ins)
+	if len(attrs) != 3 {
+		t.Fatalf("期望 3 个属性, 得到 %d", len(attrs))
+	}
+
+	// 常量属性忽略块参照中的同名 ATTRIB，缺少的属性按插入位置取默认值
+	want := map[string]string{"类型": "C", "面积": "1.2°", "备注": "窗户"}
+	for k, v := range ResolveAttrs(doc, ins) {
+		if want[k] != v {
+			t.Errorf("属性 %s 期望 %q, 得到 %q", k, want[k], v)
+		}
+	}
+	if a := attrs[1]; a.Type() != "ATTRIB" || a.Layer() != "PJ" || !near(a.Location, core.Point{X: 100, Y: -5}) || a.Rotation != 90 {
+		t.Errorf("默认属性不正确: %+v", a)
+	}
+
+	// 多行属性与属性定义原样写出
+	var buf bytes.Buffer
+	if _, err = doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	doc, err = dxf.Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defs = doc.Block("C1").AttDefs()
+	if len(defs) != 3 || defs[1].Prompt != "面积" || !defs[1].LockPosition || defs[2].Value() != "第一行\n第二行" {
+		t.Errorf("写出后 ATTDEF 不正确: %+v", defs)
+	}
+}
+
+func TestGetAttrs(t *testing.T) {
+	ins := entities.NewInsert("0", "A", core.Point{})
+	ins.Attributes = append(ins.Attributes, &entities.Attrib{Tag: " 面积", Text: " 12.5 "})
+
+	// 标签去除首尾空格，值保持原样
+	if v := GetAttr(ins, "面积"); v != " 12.5 " {
+		t.Errorf("期望 %q, 得到 %q", " 12.5 ", v)
+	}
+}
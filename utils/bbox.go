@@ -1,60 +1,45 @@
 package utils
 
 import (
+	"iter"
 	"math"
-	"strings"
+	"slices"
 
 	"github.com/zooyer/dxf"
 	"github.com/zooyer/dxf/core"
 	"github.com/zooyer/dxf/entities"
 )
 
-// TransformBBox 执行矩阵变换：将局部坐标变换到插入点所在的世界坐标
+// TransformBBox 执行矩阵变换：将局部坐标变换到插入点所在的世界坐标，块基点视为原点
+// 阵列块参照返回所有单元的合并范围
 func TransformBBox(local core.BBox, ins *entities.Insert) core.BBox {
-	rad := ins.Rotation * math.Pi / 180.0
-	cos, sin := math.Cos(rad), math.Sin(rad)
-
-	corners := []core.Point{
-		{X: local.Min.X, Y: local.Min.Y, Z: local.Min.Z},
-		{X: local.Max.X, Y: local.Min.Y, Z: local.Min.Z},
-		{X: local.Max.X, Y: local.Max.Y, Z: local.Min.Z},
-		{X: local.Min.X, Y: local.Max.Y, Z: local.Min.Z},
-		{X: local.Min.X, Y: local.Min.Y, Z: local.Max.Z},
-		{X: local.Max.X, Y: local.Min.Y, Z: local.Max.Z},
-		{X: local.Max.X, Y: local.Max.Y, Z: local.Max.Z},
-		{X: local.Min.X, Y: local.Max.Y, Z: local.Max.Z},
-	}
-
-	wMinX, wMinY, wMinZ := math.MaxFloat64, math.MaxFloat64, math.MaxFloat64
-	wMaxX, wMaxY, wMaxZ := -math.MaxFloat64, -math.MaxFloat64, -math.MaxFloat64
-
-	for _, p := range corners {
-		// 缩放
-		tx, ty, tz := p.X*ins.Scale.X, p.Y*ins.Scale.Y, p.Z*ins.Scale.Z
-
-		// XY 旋转（绕 Z 轴）
-		rx := tx*cos - ty*sin
-		ry := tx*sin + ty*cos
-
-		// 平移
-		wx, wy, wz := rx+ins.InsertionPoint.X, ry+ins.InsertionPoint.Y, tz+ins.InsertionPoint.Z
+	return TransformBBoxWithBase(local, ins, core.Point{})
+}
 
-		wMinX = math.Min(wMinX, wx)
-		wMinY = math.Min(wMinY, wy)
-		wMinZ = math.Min(wMinZ, wz)
-		wMaxX = math.Max(wMaxX, wx)
-		wMaxY = math.Max(wMaxY, wy)
-		wMaxZ = math.Max(wMaxZ, wz)
+// TransformBBoxWithBase 同 TransformBBox，base 为所插入块的基点
+func TransformBBoxWithBase(local core.BBox, ins *entities.Insert, base core.Point) core.BBox {
+	corners := ins.CornerTransforms(base)
+	box := corners[0].ApplyBBox(local)
+	for _, m := range corners[1:] {
+		box = UnionBBox(box, m.ApplyBBox(local))
 	}
+	return box
+}
 
-	return core.BBox{
-		Min: core.Point{X: wMinX, Y: wMinY, Z: wMinZ},
-		Max: core.Point{X: wMaxX, Y: wMaxY, Z: wMaxZ},
+// TransformBBoxes 依次产出阵列块参照每个单元在插入点所在坐标系下的包围盒，普通块参照只有一个
+func TransformBBoxes(local core.BBox, ins *entities.Insert, base core.Point) iter.Seq[core.BBox] {
+	return func(yield func(core.BBox) bool) {
+		for m := range ins.Transforms(base) {
+			if !yield(m.ApplyBBox(local)) {
+				return
+			}
+		}
 	}
 }
 
-// MergeBoxes 合并重叠的矩形
+// MergeBoxes 合并重叠的矩形，无限的包围盒 (构造线、射线) 被忽略
 func MergeBoxes(boxes []core.BBox, gap float64) []core.BBox {
+	boxes = slices.DeleteFunc(slices.Clone(boxes), func(b core.BBox) bool { return !b.Bounded() })
 	if len(boxes) < 2 {
 		return boxes
 	}
@@ -103,31 +88,194 @@ func InBox(box core.BBox, point core.Point) bool {
 	return false
 }
 
+// GetEntityBBoxWCS 计算实体在世界坐标下的包围盒，块参照会递归展开嵌套块
+// 需要计算大量实体时使用 NewBBoxCache 复用块的包围盒
 func GetEntityBBoxWCS(d *dxf.Document, entity entities.Entity) core.BBox {
-	switch e := entity.(type) {
-	case *entities.Insert:
-		block, ok := d.Blocks[strings.ToUpper(e.BlockName)]
-		if !ok || len(block.Entities) == 0 {
-			return core.BBox{Min: e.InsertionPoint, Max: e.InsertionPoint}
+	return NewBBoxCache(d).BBox(entity)
+}
+
+// BBoxCache 计算世界坐标包围盒，块参照逐级组合变换矩阵，直到块中的图元
+// 每个块缓存块坐标系下的包围盒，只在变换保持坐标轴方向 (平移、缩放、镜像与 90° 旋转) 时直接变换缓存，
+// 其他变换 (如任意角度旋转) 下缓存的包围盒会被放大，此时按组合后的矩阵重新计算图元
+// 文档的 VisibleOnly 为 true 时，有效图层关闭或冻结的实体不计入范围 (参见 dxf.Document.Hidden)
+// 文档中的块被修改后需要重新创建
+type BBoxCache struct {
+	doc      *dxf.Document
+	blocks   map[blockKey]*blockBBox
+	visiting map[*dxf.Block]bool // 正在计算的块，再次遇到说明块引用了自身
+}
+
+// blockKey 块中 "0" 层上的实体继承块参照的图层，块的范围与继承的图层是否隐藏有关
+type blockKey struct {
+	block  *dxf.Block
+	hidden bool
+}
+
+type blockBBox struct {
+	box core.BBox
+	ok  bool // 块中是否有图形
+}
+
+func NewBBoxCache(d *dxf.Document) *BBoxCache {
+	return &BBoxCache{doc: d, blocks: make(map[blockKey]*blockBBox), visiting: make(map[*dxf.Block]bool)}
+}
+
+// BBox 返回实体在世界坐标下的包围盒，块不存在、为空或引用自身时返回插入点
+// 构造线、射线返回无限的包围盒，合并前应以 core.BBox.Bounded 判断；
+// 没有几何图形的实体返回零值包围盒，合并前应以 entities.IsEmpty 判断
+func (c *BBoxCache) BBox(entity entities.Entity) core.BBox {
+	if box, ok := c.entity(entity, core.Identity(), c.doc.Hidden("0")); ok {
+		return box
+	}
+	return entity.BBox()
+}
+
+// Cells 依次产出阵列块参照每个单元在世界坐标下的包围盒，其他实体只有一个
+func (c *BBoxCache) Cells(entity entities.Entity) iter.Seq[core.BBox] {
+	return func(yield func(core.BBox) bool) {
+		ins, ok := entity.(*entities.Insert)
+		if !ok {
+			yield(c.BBox(entity))
+			return
+		}
+		block := c.doc.Block(ins.BlockName)
+		if block == nil {
+			yield(c.BBox(entity))
+			return
+		}
+
+		found, hidden := false, c.hidden(ins, c.doc.Hidden("0"))
+		for cell := range ins.Transforms(block.BasePoint) {
+			box, ok := c.insert(block, cell, hidden)
+			if !ok {
+				continue
+			}
+			if found = true; !yield(box) {
+				return
+			}
+		}
+		if !found {
+			yield(c.BBox(entity))
+		}
+	}
+}
+
+// Block 返回块在块坐标系下的包围盒，块为空或引用自身时 ok 为 false
+func (c *BBoxCache) Block(name string) (box core.BBox, ok bool) {
+	block := c.doc.Block(name)
+	if block == nil {
+		return
+	}
+	return c.block(block, c.doc.Hidden("0"))
+}
+
+// hidden 判断实体的有效图层是否隐藏，inherited 为 "0" 层实体继承的图层是否隐藏
+func (c *BBoxCache) hidden(entity entities.Entity, inherited bool) bool {
+	if layer := entity.Layer(); layer != "" && layer != "0" {
+		return c.doc.Hidden(layer)
+	}
+	return inherited
+}
+
+// entity 计算实体经过 m 变换后的包围盒，可变换的实体先变换几何图形再计算
+// inherited 为 "0" 层实体继承的图层是否隐藏，隐藏的实体没有包围盒
+func (c *BBoxCache) entity(entity entities.Entity, m core.Matrix, inherited bool) (core.BBox, bool) {
+	hidden := c.hidden(entity, inherited)
+	ins, ok := entity.(*entities.Insert)
+	if !ok {
+		if hidden || entities.IsEmpty(entity) {
+			return core.BBox{}, false
+		}
+		if t, ok := entity.(entities.Transformer); ok && m != core.Identity() {
+			return t.Transformed(m).BBox(), true
 		}
+		box := entity.BBox()
+		if m == core.Identity() || !box.Bounded() {
+			return box, true
+		}
+		return m.ApplyBBox(box), true
+	}
 
-		miX, miY := math.MaxFloat64, math.MaxFloat64
-		maX, maY := -math.MaxFloat64, -math.MaxFloat64
+	block := c.doc.Block(ins.BlockName)
+	if block == nil {
+		return core.BBox{}, false
+	}
 
-		for _, sub := range block.Entities {
-			sb := sub.BBox()
-			miX = math.Min(miX, sb.Min.X)
-			miY = math.Min(miY, sb.Min.Y)
-			maX = math.Max(maX, sb.Max.X)
-			maY = math.Max(maY, sb.Max.Y)
+	var (
+		box   core.BBox
+		found bool
+	)
+	// 各单元只相差一个平移，角上单元的合并范围就是整个阵列的范围
+	for _, cell := range ins.CornerTransforms(block.BasePoint) {
+		b, ok := c.insert(block, m.Multiply(cell), hidden)
+		if !ok {
+			continue
+		}
+		if found {
+			b = UnionBBox(box, b)
 		}
+		box, found = b, true
+	}
+	return box, found
+}
 
-		localBox := core.BBox{
-			Min: core.Point{X: miX, Y: miY, Z: 0},
-			Max: core.Point{X: maX, Y: maY, Z: 0},
+// insert 计算块经过 m (块坐标到目标坐标) 变换后的包围盒，hidden 为块参照的有效图层是否隐藏
+func (c *BBoxCache) insert(block *dxf.Block, m core.Matrix, hidden bool) (core.BBox, bool) {
+	if m.AxisAligned() {
+		local, ok := c.block(block, hidden)
+		if !ok {
+			return core.BBox{}, false
 		}
-		return TransformBBox(localBox, e)
-	default:
-		return e.BBox()
+		return m.ApplyBBox(local), true
+	}
+
+	if c.visiting[block] {
+		return core.BBox{}, false
+	}
+	c.visiting[block] = true
+	defer delete(c.visiting, block)
+
+	return c.entities(block.Entities, m, hidden)
+}
+
+// block 返回块在块坐标系下的包围盒并缓存
+func (c *BBoxCache) block(block *dxf.Block, hidden bool) (core.BBox, bool) {
+	key := blockKey{block: block, hidden: hidden}
+	if cached, ok := c.blocks[key]; ok {
+		return cached.box, cached.ok
+	}
+	if c.visiting[block] {
+		return core.BBox{}, false
+	}
+
+	c.visiting[block] = true
+	box, ok := c.entities(block.Entities, core.Identity(), hidden)
+	delete(c.visiting, block)
+
+	c.blocks[key] = &blockBBox{box: box, ok: ok}
+	return box, ok
+}
+
+// entities 合并实体经过 m 变换后的包围盒，inherited 为 "0" 层实体继承的图层是否隐藏
+func (c *BBoxCache) entities(list []entities.Entity, m core.Matrix, inherited bool) (box core.BBox, ok bool) {
+	for _, sub := range list {
+		// 构造线、射线等无限长的实体、没有几何图形与隐藏图层上的实体不计入块的范围
+		b, found := c.entity(sub, m, inherited)
+		if !found || !b.Bounded() {
+			continue
+		}
+		if ok {
+			b = UnionBBox(box, b)
+		}
+		box, ok = b, true
+	}
+	return
+}
+
+// UnionBBox 返回同时包含 a、b 的最小包围盒
+func UnionBBox(a, b core.BBox) core.BBox {
+	return core.BBox{
+		Min: core.Point{X: math.Min(a.Min.X, b.Min.X), Y: math.Min(a.Min.Y, b.Min.Y), Z: math.Min(a.Min.Z, b.Min.Z)},
+		Max: core.Point{X: math.Max(a.Max.X, b.Max.X), Y: math.Max(a.Max.Y, b.Max.Y), Z: math.Max(a.Max.Z, b.Max.Z)},
 	}
 }
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/zooyer/dxf"
+	"github.com/zooyer/dxf/core"
+	"github.com/zooyer/dxf/entities"
+)
+
+func TestGetEntityBBoxWCS(t *testing.T) {
+	doc := dxf.New()
+
+	// INNER: 基点 (10,10)，一条 (10,10)-(12,11) 的直线
+	doc.Blocks["INNER"] = &dxf.Block{
+		Name:      "INNER",
+		BasePoint: core.Point{X: 10, Y: 10},
+		Entities: []entities.Entity{
+			entities.NewLine("0", core.Point{X: 10, Y: 10}, core.Point{X: 12, Y: 11}),
+			entities.NewXLine("0", core.Point{X: 10, Y: 10}, core.Point{X: 1, Y: 1}), // 构造线不计入范围
+			entities.NewRawEntity("WIPEOUT"),                                         // 没有几何图形的实体不计入范围
+			entities.NewLWPolyline("0"),
+		},
+	}
+
+	// OUTER: 在 (5,0) 处旋转 90° 插入 INNER，并引用自身
+	inner := entities.NewInsert("0", "inner", core.Point{X: 5})
+	inner.Rotation = 90
+	doc.Blocks["OUTER"] = &dxf.Block{
+		Name:     "OUTER",
+		Entities: []entities.Entity{inner, entities.NewInsert("0", "OUTER", core.Point{X: 1000})},
+	}
+
+	outer := entities.NewInsert("0", "OUTER", core.Point{X: 100, Y: 100})
+	outer.Scale = core.Point{X: 2, Y: 2, Z: 1}
+
+	box := GetEntityBBoxWCS(doc, outer)
+	want := core.BBox{Min: core.Point{X: 108, Y: 100}, Max: core.Point{X: 110, Y: 104}}
+	if !near(box.Min, want.Min) || !near(box.Max, want.Max) {
+		t.Errorf("期望 %+v, 得到 %+v", want, box)
+	}
+
+	// 1 列 2 行、行距 10 的阵列：两个单元分别返回，合并范围覆盖两者
+	grid := entities.NewMInsert("0", "INNER", core.Point{}, 1, 2, 0, 10)
+	cells := slices.Collect(NewBBoxCache(doc).Cells(grid))
+	if len(cells) != 2 || !near(cells[1].Min, core.Point{Y: 10}) || !near(cells[1].Max, core.Point{X: 2, Y: 11}) {
+		t.Errorf("阵列单元不正确: %+v", cells)
+	}
+	if box = GetEntityBBoxWCS(doc, grid); !near(box.Max, core.Point{X: 2, Y: 11}) {
+		t.Errorf("阵列范围不正确: %+v", box)
+	}
+
+	// 行列数取最大值的阵列不逐个计算单元
+	huge := entities.NewMInsert("0", "INNER", core.Point{}, 32767, 32767, 1, 1)
+	if box = GetEntityBBoxWCS(doc, huge); !near(box.Max, core.Point{X: 32768, Y: 32767}) {
+		t.Errorf("大阵列范围不正确: %+v", box)
+	}
+	for cell := range NewBBoxCache(doc).Cells(huge) {
+		if !near(cell.Min, core.Point{}) {
+			t.Errorf("第一个单元不正确: %+v", cell)
+		}
+		break
+	}
+}
+
+func TestBBoxCache_Rotated(t *testing.T) {
+	doc := dxf.New()
+
+	// LINE: (0,0)-(10,0) 的直线；ARM: 旋转 45° 插入 LINE；圆 R1 在原点
+	doc.Blocks["LINE"] = &dxf.Block{Name: "LINE", Entities: []entities.Entity{
+		entities.NewLine("0", core.Point{}, core.Point{X: 10}),
+		entities.NewCircle("0", core.Point{}, 1),
+	}}
+	arm := entities.NewInsert("0", "LINE", core.Point{})
+	arm.Rotation = 45
+	doc.Blocks["ARM"] = &dxf.Block{Name: "ARM", Entities: []entities.Entity{arm}}
+
+	// 再旋转 -45° 插入 ARM，组合后直线回到 X 轴上，包围盒不应被逐级放大
+	outer := entities.NewInsert("0", "ARM", core.Point{X: 100})
+	outer.Rotation = -45
+
+	cache := NewBBoxCache(doc)
+	box := cache.BBox(outer)
+	want := core.BBox{Min: core.Point{X: 99, Y: -1}, Max: core.Point{X: 110, Y: 1}}
+	if !near(box.Min, want.Min) || !near(box.Max, want.Max) {
+		t.Errorf("期望 %+v, 得到 %+v", want, box)
+	}
+
+	// 块坐标系下的包围盒已缓存，旋转 30° 的插入仍按图元精确计算
+	single := entities.NewInsert("0", "LINE", core.Point{})
+	single.Rotation = 30
+	box = cache.BBox(single)
+	if !near(box.Min, core.Point{X: -1, Y: -1}) || math.Abs(box.Max.Y-5) > 1e-9 {
+		t.Errorf("旋转后的包围盒不正确: %+v", box)
+	}
+}
+
+func TestBBoxCache_VisibleOnly(t *testing.T) {
+	doc := dxf.New()
+	doc.VisibleOnly = true
+	doc.Layers["OFF"] = &dxf.Layer{Name: "OFF", Off: true}
+
+	// 0 层的直线继承块参照的图层，WALL 层的直线不受影响
+	doc.Blocks["A"] = &dxf.Block{Name: "A", Entities: []entities.Entity{
+		entities.NewLine("0", core.Point{}, core.Point{X: 10}),
+		entities.NewLine("WALL", core.Point{}, core.Point{Y: 5}),
+	}}
+
+	cache := NewBBoxCache(doc)
+	if box := cache.BBox(entities.NewInsert("OFF", "A", core.Point{})); box.Max != (core.Point{Y: 5}) {
+		t.Errorf("隐藏图层上的块参照应只包含 WALL 层: %+v", box)
+	}
+	if box := cache.BBox(entities.NewInsert("WALL", "A", core.Point{})); box.Max != (core.Point{X: 10, Y: 5}) {
+		t.Errorf("可见图层上的块参照应包含全部实体: %+v", box)
+	}
+}
+
+func near(a, b core.Point) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+}
+
+func TestTransformBBox(t *testing.T) {
+	local := core.BBox{Min: core.Point{X: 10, Y: 10}, Max: core.Point{X: 12, Y: 11}}
+	ins := entities.NewInsert("0", "A", core.Point{X: 100})
+
+	if got := TransformBBox(local, ins); got.Min != (core.Point{X: 110, Y: 10}) || got.Max != (core.Point{X: 112, Y: 11}) {
+		t.Errorf("TransformBBox = %+v", got)
+	}
+	if got := TransformBBoxWithBase(local, ins, core.Point{X: 10, Y: 10}); got.Min != (core.Point{X: 100}) || got.Max != (core.Point{X: 102, Y: 1}) {
+		t.Errorf("TransformBBoxWithBase = %+v", got)
+	}
+	if got := TransformPoint(core.Point{X: 1}, ins); got != (core.Point{X: 101}) {
+		t.Errorf("TransformPoint = %+v", got)
+	}
+}
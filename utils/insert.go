@@ -5,8 +5,19 @@ import (
 	"github.com/zooyer/dxf/entities"
 )
 
-// CombineInserts 合并嵌套块的变换矩阵逻辑
+// CombineInserts 合并嵌套块的变换矩阵逻辑，父级所插入块的基点视为原点
+//
+// Deprecated: 父块非等比或镜像缩放且子块旋转时，合并结果是错切变换，无法用 Insert 表示。
+// 请使用 Insert.Transform 得到的 core.Matrix 逐级相乘。
 func CombineInserts(parent, child *entities.Insert) *entities.Insert {
+	return CombineInsertsWithBase(parent, child, core.Point{})
+}
+
+// CombineInsertsWithBase 同 CombineInserts，base 为父级所插入块的基点
+// 合并结果插入的是子块，使用时应传入子块的基点
+//
+// Deprecated: 同 CombineInserts，请使用 Insert.Transform 得到的 core.Matrix 逐级相乘。
+func CombineInsertsWithBase(parent, child *entities.Insert, base core.Point) *entities.Insert {
 	// 1. 旋转叠加
 	combinedRotation := parent.Rotation + child.Rotation
 
@@ -18,7 +29,7 @@ func CombineInserts(parent, child *entities.Insert) *entities.Insert {
 	}
 
 	// 3. 插入点叠加：子块的插入点需要经过父块的 缩放 -> 旋转 -> 平移 变换
-	combinedInsertionPoint := TransformPoint(child.InsertionPoint, parent)
+	combinedInsertionPoint := TransformPointWithBase(child.InsertionPoint, parent, base)
 
 	return &entities.Insert{
 		BlockName:      child.BlockName,
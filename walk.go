@@ -0,0 +1,197 @@
+package dxf
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/zooyer/dxf/core"
+	"github.com/zooyer/dxf/entities"
+)
+
+// SkipAll 由 Visitor 的回调返回，表示停止遍历，Walk 返回 nil
+var SkipAll = errors.New("dxf: skip all")
+
+// Visitor 接收 Walk 逐个解析出的内容，不需要的回调可以为 nil
+//
+// 回调中的 doc 包含已经读取的 HEADER、TABLES 与 BLOCKS，但不保存 ENTITIES 段的实体
+type Visitor struct {
+	// Filter 在创建 ENTITIES 段的实体之前调用，返回 false 时跳过该实体 (连同其 ATTRIB、VERTEX)
+	// typ 为大写的实体类型，layer 为实体所在图层
+	Filter func(typ, layer string) bool
+
+	// Entity 每解析出一个 ENTITIES 段的实体时调用
+	Entity func(doc *Document, ent entities.Entity) error
+
+	// Section 每读完一个段时调用，name 为大写的段名
+	Section func(doc *Document, name string) error
+}
+
+// Walk 流式解析 DXF，逐个把实体交给 visitor，不在内存中保存 ENTITIES 段
+// 返回的文档包含 HEADER、TABLES、BLOCKS 与其他段
+func Walk(reader io.Reader, visitor Visitor, opts ...Option) (*Document, error) {
+	return walk(context.Background(), reader, &visitor, newOptions(opts))
+}
+
+// WalkFile 打开文件并流式解析，参见 Walk
+func WalkFile(filename string, visitor Visitor, opts ...Option) (doc *Document, err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if e := file.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
+
+	return Walk(file, visitor, opts...)
+}
+
+func walk(ctx context.Context, reader io.Reader, visitor *Visitor, options *options) (*Document, error) {
+	var (
+		progress = newProgressReader(ctx, reader, options.progress)
+		scanner  = core.NewScanner(progress)
+		document = New()
+	)
+	document.VisibleOnly = options.visible
+
+	if options.encoding != nil {
+		scanner.SetEncoding(options.encoding)
+	}
+	scanner.SetStrict(options.strict)
+
+	err := document.walk(scanner, visitor, options, progress)
+	document.Warnings = scanner.Warnings()
+	if errors.Is(err, SkipAll) {
+		err = nil
+	}
+	if err == nil {
+		err = scanner.Err()
+	}
+	if err != nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+
+	return document, err
+}
+
+func (d *Document) walk(scanner *core.Scanner, visitor *Visitor, options *options, progress *progressReader) error {
+	for scanner.Next() {
+		tag := scanner.LastTag
+		if tag.Code != 0 || strings.ToUpper(tag.Value) != "SECTION" {
+			continue
+		}
+		if !scanner.Next() {
+			break
+		}
+
+		sectionName := strings.ToUpper(scanner.LastTag.Value)
+		progress.section(sectionName)
+		switch sectionName {
+		case "HEADER":
+			d.parseHeader(scanner)
+			if options.encoding == nil {
+				scanner.SetEncoding(d.encoding())
+			}
+		case "TABLES":
+			d.parseTables(scanner)
+		case "BLOCKS":
+			d.parseBlocks(scanner)
+		case "ENTITIES":
+			if err := d.parseEntities(scanner, visitor); err != nil {
+				return err
+			}
+		default:
+			d.parseSection(scanner, scanner.LastTag.Value)
+		}
+
+		if visitor.Section != nil {
+			if err := visitor.Section(d, sectionName); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseEntities 逐个解析 ENTITIES 段的实体并交给 visitor
+func (d *Document) parseEntities(scanner *core.Scanner, visitor *Visitor) error {
+	for scanner.Next() {
+		tag := scanner.LastTag
+		if tag.Code != 0 {
+			continue
+		}
+
+		typ := strings.ToUpper(tag.Value)
+		if typ == "ENDSEC" {
+			return nil
+		}
+
+		if visitor.Filter != nil || d.VisibleOnly {
+			if !d.accept(visitor, typ, entityLayer(scanner.PeekEntity())) {
+				skipEntity(scanner)
+				continue
+			}
+		}
+
+		ent := entities.CreateEntity(tag.Value)
+		_ = ent.Parse(scanner)
+		if visitor.Entity != nil {
+			if err := visitor.Entity(d, ent); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// accept 判断是否解析该实体
+func (d *Document) accept(visitor *Visitor, typ, layer string) bool {
+	if d.Hidden(layer) {
+		return false
+	}
+	return visitor.Filter == nil || visitor.Filter(typ, layer)
+}
+
+// entityLayer 从实体的标签中找出图层名 (组码 8)，忽略 102 组内的标签
+func entityLayer(tags []core.Tag) string {
+	var inGroup bool
+	for _, tag := range tags {
+		switch {
+		case tag.Code == 102:
+			inGroup = strings.HasPrefix(tag.Value, "{")
+		case tag.Code == 8 && !inGroup:
+			return tag.Value
+		}
+	}
+	return ""
+}
+
+// skipEntity 跳过当前实体，以及紧随其后的 ATTRIB、VERTEX 与 SEQEND
+func skipEntity(scanner *core.Scanner) {
+	scanner.Skip()
+	for {
+		tag, ok := scanner.Peek()
+		if !ok || tag.Code != 0 {
+			return
+		}
+
+		switch strings.ToUpper(tag.Value) {
+		case "ATTRIB", "VERTEX":
+			scanner.Next()
+			scanner.Skip()
+		case "SEQEND":
+			scanner.Next()
+			scanner.Skip()
+			return
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package dxf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zooyer/dxf/entities"
+)
+
+func TestWalk(t *testing.T) {
+	data := "0\nSECTION\n2\nBLOCKS\n" +
+		"0\nBLOCK\n2\nDOOR\n0\nLINE\n8\n0\n0\nENDBLK\n" +
+		"0\nENDSEC\n" +
+		"0\nSECTION\n2\nENTITIES\n" +
+		"0\nINSERT\n5\nA1\n8\nWALL\n66\n1\n2\nDOOR\n0\nATTRIB\n1\nx\n2\nNO\n0\nSEQEND\n" +
+		"0\nINSERT\n5\nA2\n8\nDOOR\n2\nDOOR\n" +
+		"0\nLINE\n5\nA3\n8\nDOOR\n" +
+		"0\nINSERT\n5\nA4\n8\nDOOR\n66\n1\n2\nDOOR\n0\nATTRIB\n1\ny\n2\nNO\n0\nSEQEND\n" +
+		"0\nINSERT\n5\nA5\n8\nDOOR\n2\nDOOR\n" +
+		"0\nENDSEC\n0\nEOF\n"
+
+	var handles []string
+	doc, err := Walk(strings.NewReader(data), Visitor{
+		Filter: func(typ, layer string) bool {
+			return typ == "INSERT" && layer == "DOOR"
+		},
+		Entity: func(doc *Document, ent entities.Entity) error {
+			if doc.Blocks["DOOR"] == nil {
+				t.Error("实体回调中应能访问已读取的块")
+			}
+			handles = append(handles, ent.Base().Handle)
+			if ent.(*entities.Insert).Handle == "A4" {
+				if n := len(ent.(*entities.Insert).Attributes); n != 1 {
+					t.Errorf("期望 1 个属性, 得到 %d", n)
+				}
+				return SkipAll
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(handles, ",") != "A2,A4" {
+		t.Errorf("期望 A2,A4, 得到 %v", handles)
+	}
+	if len(doc.Entities) != 0 {
+		t.Errorf("Walk 不应保存实体, 得到 %d 个", len(doc.Entities))
+	}
+}
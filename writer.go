@@ -0,0 +1,721 @@
+package dxf
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/zooyer/dxf/core"
+	"github.com/zooyer/dxf/entities"
+)
+
+// 新建文档写出的图纸版本 R2000，非 ASCII 字符以 \U+XXXX 转义，代码页默认为 ANSI_1252
+const (
+	writeVersion  = "AC1015"
+	writeCodePage = "ANSI_1252"
+)
+
+// tableOrder 符号表的标准顺序
+var tableOrder = []string{"VPORT", "LTYPE", "LAYER", "STYLE", "VIEW", "UCS", "APPID", "DIMSTYLE", "BLOCK_RECORD"}
+
+// Save 将文档保存为 ASCII DXF 文件
+func (d *Document) Save(filename string) (err error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if e := file.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
+
+	_, err = d.WriteTo(file)
+
+	return
+}
+
+// WriteTo 将文档序列化为 ASCII DXF，依次写出 HEADER、CLASSES、TABLES、BLOCKS、ENTITIES、OBJECTS
+// 加载得到的表记录、未解析的段与实体原样写出，已有句柄保持不变，新建的对象从 $HANDSEED 开始分配句柄
+// 新分配的句柄与所属对象只用于本次写出，不修改文档
+func (d *Document) WriteTo(writer io.Writer) (n int64, err error) {
+	// 句柄在写出各段时才分配完，所以先写正文，最后补上带 $HANDSEED 的 HEADER
+	var body bytes.Buffer
+	dw := &docWriter{doc: d, w: core.NewWriter(&body)}
+	dw.w.SetUTF8(dw.version() >= "AC1021")
+
+	dw.reserve()
+	dw.writeSection(d.Section("CLASSES"))
+	dw.writeTables()
+	dw.writeBlocks()
+	dw.writeEntities()
+	dw.writeObjects()
+	for _, section := range d.Sections {
+		if name := strings.ToUpper(section.Name); name != "CLASSES" && name != "OBJECTS" {
+			dw.writeSection(section)
+		}
+	}
+	dw.w.WriteString(0, "EOF")
+	if err = dw.w.Flush(); err != nil {
+		return
+	}
+
+	var head bytes.Buffer
+	hw := core.NewWriter(&head)
+	hw.SetUTF8(dw.version() >= "AC1021")
+	dw.writeHeader(hw)
+	if err = hw.Flush(); err != nil {
+		return
+	}
+
+	return io.Copy(writer, io.MultiReader(&head, &body))
+}
+
+type docWriter struct {
+	doc     *Document
+	w       *core.Writer
+	records map[string]string // 块名 (大写) → BLOCK_RECORD 句柄
+}
+
+// version 写出的图纸版本，低于 R2000 的图纸升级为 R2000
+func (dw *docWriter) version() string {
+	if version := dw.doc.Header.Version(); version >= writeVersion {
+		return version
+	}
+	return writeVersion
+}
+
+// eachEntity 遍历文档中的所有实体 (含块内实体)
+func (dw *docWriter) eachEntity(fn func(e entities.Entity)) {
+	for _, e := range dw.doc.Entities {
+		fn(e)
+	}
+	for _, block := range dw.doc.Blocks {
+		for _, e := range block.Entities {
+			fn(e)
+		}
+	}
+}
+
+// reserve 保留文档中已存在的句柄，包括原样写出的表、表记录与未解析的段
+func (dw *docWriter) reserve() {
+	dw.w.SetHandleSeed(dw.doc.Header.String("$HANDSEED"))
+	for _, table := range dw.doc.Tables {
+		dw.w.Reserve(table.Handle())
+		for _, record := range table.Records {
+			dw.w.Reserve(record.Handle)
+		}
+	}
+	for _, section := range dw.doc.Sections {
+		for _, tag := range section.Tags {
+			if tag.Code == 5 || tag.Code == 105 {
+				dw.w.Reserve(tag.Value)
+			}
+		}
+	}
+	for _, block := range dw.doc.Blocks {
+		dw.w.Reserve(block.Handle)
+		dw.w.Reserve(block.EndHandle)
+	}
+	for _, layer := range dw.doc.Layers {
+		dw.w.Reserve(layer.Handle)
+	}
+	for _, style := range dw.doc.DimStyles {
+		dw.w.Reserve(style.Handle)
+	}
+	dw.eachEntity(func(e entities.Entity) {
+		dw.w.Reserve(e.Base().Handle)
+		switch e := e.(type) {
+		case *entities.Insert:
+			for _, attr := range e.Attributes {
+				dw.w.Reserve(attr.Handle)
+			}
+			dw.w.Reserve(e.SeqEnd)
+		case *entities.Polyline:
+			for _, vertex := range e.Vertices {
+				dw.w.Reserve(vertex.Handle)
+			}
+			dw.w.Reserve(e.SeqEnd)
+		}
+	})
+}
+
+func (dw *docWriter) writeHeader(w *core.Writer) {
+	codePage := dw.doc.Header.CodePage()
+	if codePage == "" {
+		codePage = writeCodePage
+	}
+
+	override := map[string]core.Tag{
+		"$DWGCODEPAGE": {Code: 3, Value: codePage},
+		"$HANDSEED":    {Code: 5, Value: dw.w.HandleSeed()},
+	}
+
+	w.WriteString(0, "SECTION")
+	w.WriteString(2, "HEADER")
+	w.WriteString(9, "$ACADVER")
+	w.WriteString(1, dw.version())
+
+	var names []string
+	if dw.doc.Header != nil {
+		names = dw.doc.Header.Names
+	}
+	for _, name := range []string{"$DWGCODEPAGE", "$HANDSEED"} {
+		if dw.doc.Header == nil || !dw.doc.Header.Has(name) {
+			names = append([]string{name}, names...)
+		}
+	}
+
+	for _, name := range names {
+		if name == "$ACADVER" {
+			continue
+		}
+		w.WriteString(9, name)
+		if tag, ok := override[name]; ok {
+			w.WriteString(tag.Code, tag.Value)
+			continue
+		}
+		for _, tag := range dw.doc.Header.Vars[name] {
+			w.WriteString(tag.Code, tag.Value)
+		}
+	}
+
+	w.WriteString(0, "ENDSEC")
+}
+
+// writeSection 原样写出未解析的段
+func (dw *docWriter) writeSection(section *Section) {
+	if section == nil {
+		return
+	}
+
+	dw.w.WriteString(0, "SECTION")
+	dw.w.WriteString(2, section.Name)
+	for _, tag := range section.Tags {
+		dw.w.WriteString(tag.Code, tag.Value)
+	}
+	dw.w.WriteString(0, "ENDSEC")
+}
+
+func (dw *docWriter) writeTables() {
+	w := dw.w
+
+	w.WriteString(0, "SECTION")
+	w.WriteString(2, "TABLES")
+
+	names := append([]string{}, tableOrder...)
+	var others []string
+	for name := range dw.doc.Tables {
+		if !slices.Contains(tableOrder, name) {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+
+	dw.records = make(map[string]string)
+	for _, name := range append(names, others...) {
+		dw.writeTable(name)
+	}
+
+	w.WriteString(0, "ENDSEC")
+}
+
+// writeTable 写出符号表：加载得到的记录原样写出，再补充缺少的记录
+func (dw *docWriter) writeTable(name string) {
+	var (
+		w       = dw.w
+		raw     = dw.doc.Tables[name]
+		records []*entities.RawEntity
+		handle  string
+	)
+
+	if raw != nil {
+		handle = raw.Handle()
+		for _, record := range raw.Records {
+			if dw.keepRecord(name, record) {
+				records = append(records, record)
+			}
+		}
+	}
+	if handle == "" {
+		handle = w.Handle()
+	}
+
+	entries := dw.entries(name, raw)
+
+	w.WriteString(0, "TABLE")
+	w.WriteString(2, name)
+	if raw != nil && len(raw.Tags) > 0 {
+		for _, tag := range raw.Tags {
+			if tag.Code == 70 {
+				w.WriteInt(70, len(records)+len(entries))
+				continue
+			}
+			w.WriteString(tag.Code, tag.Value)
+		}
+	} else {
+		w.WriteString(5, handle)
+		w.WriteString(330, "0")
+		w.WriteString(100, "AcDbSymbolTable")
+		w.WriteInt(70, len(records)+len(entries))
+		if name == "DIMSTYLE" {
+			w.WriteString(100, "AcDbDimStyleTable")
+		}
+	}
+
+	for _, record := range records {
+		if name == "BLOCK_RECORD" {
+			dw.records[strings.ToUpper(record.Value(2))] = record.Handle
+		}
+		_ = record.Write(w)
+	}
+	for _, entry := range entries {
+		entry(handle)
+	}
+
+	w.WriteString(0, "ENDTAB")
+}
+
+// keepRecord 判断加载得到的表记录是否原样写出
+// 图层与标注样式由 Layers、DimStyles 重新生成，已删除的块不再写出块记录
+func (dw *docWriter) keepRecord(table string, record *entities.RawEntity) bool {
+	switch table {
+	case "LAYER", "DIMSTYLE":
+		return false
+	case "BLOCK_RECORD":
+		name := strings.ToUpper(record.Value(2))
+		_, ok := dw.doc.Blocks[name]
+		return ok || name == "*MODEL_SPACE" || name == "*PAPER_SPACE"
+	}
+	return true
+}
+
+// entries 返回需要补充写出的表记录
+func (dw *docWriter) entries(table string, raw *Table) (entries []func(owner string)) {
+	w := dw.w
+
+	missing := func(name string) bool {
+		return raw == nil || raw.Record(name) == nil
+	}
+
+	switch table {
+	case "VPORT":
+		if raw == nil {
+			entries = append(entries, func(owner string) {
+				box := dw.extents()
+				width, height := box.Max.X-box.Min.X, box.Max.Y-box.Min.Y
+				dw.record("VPORT", owner, "AcDbViewportTableRecord", "*Active", 0)
+				w.WritePoint2D(10, core.Point{})
+				w.WritePoint2D(11, core.Point{X: 1, Y: 1})
+				w.WritePoint2D(12, core.Point{X: (box.Min.X + box.Max.X) / 2, Y: (box.Min.Y + box.Max.Y) / 2})
+				w.WriteFloat(40, math.Max(height, 1)*1.1)
+				w.WriteFloat(41, math.Max(width, 1)/math.Max(height, 1))
+			})
+		}
+	case "LTYPE":
+		for _, name := range []string{"ByBlock", "ByLayer", "Continuous"} {
+			if !missing(name) {
+				continue
+			}
+			entries = append(entries, func(owner string) {
+				dw.record("LTYPE", owner, "AcDbLinetypeTableRecord", name, 0)
+				if name == "Continuous" {
+					w.WriteString(3, "Solid line")
+				} else {
+					w.WriteString(3, "")
+				}
+				w.WriteInt(72, 65)
+				w.WriteInt(73, 0)
+				w.WriteFloat(40, 0)
+			})
+		}
+	case "LAYER":
+		for _, layer := range dw.layers() {
+			entries = append(entries, func(owner string) {
+				handle := layer.Handle
+				if handle == "" {
+					handle = w.Handle()
+				}
+
+				flags := layer.Flags &^ 5
+				if layer.Frozen {
+					flags |= 1
+				}
+				if layer.Locked {
+					flags |= 4
+				}
+				color := layer.Color
+				if color == 0 {
+					color = 7
+				}
+				if layer.Off {
+					color = -color
+				}
+				lineType := layer.LineType
+				if lineType == "" {
+					lineType = "Continuous"
+				}
+				plot := 0
+				if layer.Plot {
+					plot = 1
+				}
+
+				groups, extra := splitGroups(layer.Extra)
+				dw.recordWithHandle("LAYER", handle, owner, "AcDbLayerTableRecord", layer.Name, flags, groups...)
+				w.WriteInt(62, color)
+				if layer.TrueColor != 0 {
+					w.WriteInt(420, layer.TrueColor)
+				}
+				w.WriteString(6, lineType)
+				w.WriteInt(290, plot)
+				w.WriteInt(370, layer.LineWeight)
+				for _, tag := range extra {
+					w.WriteString(tag.Code, tag.Value)
+				}
+			})
+		}
+	case "STYLE":
+		if missing("Standard") {
+			entries = append(entries, func(owner string) {
+				dw.record("STYLE", owner, "AcDbTextStyleTableRecord", "Standard", 0)
+				w.WriteFloat(40, 0)
+				w.WriteFloat(41, 1)
+				w.WriteFloat(50, 0)
+				w.WriteInt(71, 0)
+				w.WriteFloat(42, 2.5)
+				w.WriteString(3, "txt")
+				w.WriteString(4, "")
+			})
+		}
+	case "APPID":
+		if missing("ACAD") {
+			entries = append(entries, func(owner string) {
+				dw.record("APPID", owner, "AcDbRegAppTableRecord", "ACAD", 0)
+			})
+		}
+	case "DIMSTYLE":
+		for _, style := range dw.dimStyles() {
+			entries = append(entries, func(owner string) {
+				handle := style.Handle
+				if handle == "" {
+					handle = w.Handle()
+				}
+				dw.recordWithHandle("DIMSTYLE", handle, owner, "AcDbDimStyleTableRecord", style.Name, style.Flags)
+				w.WriteFloat(40, style.Scale)
+				w.WriteFloat(44, style.ExLimit)
+				w.WriteInt(271, style.Precision)
+				for _, tag := range style.Extra {
+					w.WriteString(tag.Code, tag.Value)
+				}
+			})
+		}
+	case "BLOCK_RECORD":
+		names := append([]string{"*Model_Space", "*Paper_Space"}, dw.blockNames()...)
+		for _, name := range names {
+			if !missing(name) {
+				continue
+			}
+			entries = append(entries, func(owner string) {
+				dw.records[strings.ToUpper(name)] = dw.record("BLOCK_RECORD", owner, "AcDbBlockTableRecord", name, 0)
+			})
+		}
+	}
+
+	return
+}
+
+// record 写出符号表记录的公共部分，返回记录的句柄
+func (dw *docWriter) record(typ, owner, subclass, name string, flags int) string {
+	return dw.recordWithHandle(typ, dw.w.Handle(), owner, subclass, name, flags)
+}
+
+// recordWithHandle 以指定句柄写出表记录的公共部分，groups 为句柄之后的 102 组
+func (dw *docWriter) recordWithHandle(typ, handle, owner, subclass, name string, flags int, groups ...core.Tag) string {
+	w := dw.w
+
+	w.WriteString(0, typ)
+	if typ == "DIMSTYLE" {
+		w.WriteString(105, handle)
+	} else {
+		w.WriteString(5, handle)
+	}
+	for _, tag := range groups {
+		w.WriteString(tag.Code, tag.Value)
+	}
+	w.WriteString(330, owner)
+	w.WriteString(100, "AcDbSymbolTableRecord")
+	w.WriteString(100, subclass)
+	w.WriteString(2, name)
+	w.WriteInt(70, flags)
+
+	return handle
+}
+
+// writeBlock 写出一个块定义 BLOCK ... ENDBLK
+func (dw *docWriter) writeBlock(name string, block *Block) {
+	w, record := dw.w, dw.records[strings.ToUpper(name)]
+
+	if block == nil {
+		block = &Block{Name: name}
+	}
+	if block.Name != "" {
+		name = block.Name
+	}
+	handle := block.Handle
+	if handle == "" {
+		handle = w.Handle()
+	}
+
+	flags := block.Flags
+	if strings.HasPrefix(name, "*") && !isLayoutBlock(name) {
+		flags |= 1 // 匿名块
+	}
+
+	w.WriteString(0, "BLOCK")
+	w.WriteString(5, handle)
+	w.WriteString(330, record)
+	w.WriteString(100, "AcDbEntity")
+	w.WriteLayer("0")
+	w.WriteString(100, "AcDbBlockBegin")
+	w.WriteString(2, name)
+	w.WriteInt(70, flags)
+	w.WritePoint(10, block.BasePoint)
+	w.WriteString(3, name)
+	w.WriteString(1, block.XrefPath)
+	if block.Description != "" {
+		w.WriteString(4, block.Description)
+	}
+
+	for _, e := range block.Entities {
+		if w.Err() == nil {
+			w.SetOwner(record)
+			_ = e.Write(w)
+		}
+	}
+
+	end := block.EndHandle
+	if end == "" {
+		end = w.Handle()
+	}
+
+	w.WriteString(0, "ENDBLK")
+	w.WriteString(5, end)
+	w.WriteString(330, record)
+	w.WriteString(100, "AcDbEntity")
+	w.WriteLayer("0")
+	w.WriteString(100, "AcDbBlockEnd")
+}
+
+func (dw *docWriter) writeBlocks() {
+	w := dw.w
+
+	w.WriteString(0, "SECTION")
+	w.WriteString(2, "BLOCKS")
+
+	dw.writeBlock("*Model_Space", dw.doc.Blocks["*MODEL_SPACE"])
+	dw.writeBlock("*Paper_Space", dw.doc.Blocks["*PAPER_SPACE"])
+	for _, name := range dw.blockNames() {
+		dw.writeBlock(name, dw.doc.Blocks[name])
+	}
+
+	w.WriteString(0, "ENDSEC")
+}
+
+func (dw *docWriter) writeEntities() {
+	var (
+		w     = dw.w
+		model = dw.records["*MODEL_SPACE"]
+		paper = dw.records["*PAPER_SPACE"]
+	)
+
+	w.WriteString(0, "SECTION")
+	w.WriteString(2, "ENTITIES")
+
+	for _, e := range dw.doc.Entities {
+		// 图纸空间的实体保持原所属对象，其余归属模型空间
+		owner := model
+		if base := e.Base(); base.Owner == paper && paper != "" {
+			owner = paper
+		}
+		if w.Err() == nil {
+			w.SetOwner(owner)
+			_ = e.Write(w)
+		}
+	}
+
+	w.WriteString(0, "ENDSEC")
+}
+
+// writeObjects 写出 OBJECTS 段，新建文档写出 R2000 必需的根字典与 ACAD_GROUP 字典
+func (dw *docWriter) writeObjects() {
+	if section := dw.doc.Section("OBJECTS"); section != nil {
+		dw.writeSection(section)
+		return
+	}
+
+	var (
+		w     = dw.w
+		root  = w.Handle()
+		group = w.Handle()
+	)
+
+	w.WriteString(0, "SECTION")
+	w.WriteString(2, "OBJECTS")
+
+	w.WriteString(0, "DICTIONARY")
+	w.WriteString(5, root)
+	w.WriteString(330, "0")
+	w.WriteString(100, "AcDbDictionary")
+	w.WriteInt(281, 1)
+	w.WriteString(3, "ACAD_GROUP")
+	w.WriteString(350, group)
+
+	w.WriteString(0, "DICTIONARY")
+	w.WriteString(5, group)
+	w.WriteString(330, root)
+	w.WriteString(100, "AcDbDictionary")
+	w.WriteInt(281, 1)
+
+	w.WriteString(0, "ENDSEC")
+}
+
+// layers 返回需要写入 LAYER 表的图层，实体用到但未定义的图层以默认属性补充，"0" 层始终在最前
+func (dw *docWriter) layers() []*Layer {
+	var layers []*Layer
+	for _, layer := range dw.doc.Layers {
+		layers = append(layers, layer)
+	}
+	for _, name := range dw.usedLayers() {
+		if dw.doc.Layer(name) == nil {
+			layers = append(layers, newLayer(name))
+		}
+	}
+	sort.Slice(layers, func(i, j int) bool {
+		a, b := strings.ToUpper(layers[i].Name), strings.ToUpper(layers[j].Name)
+		if a == "0" || b == "0" {
+			return a == "0" && b != "0"
+		}
+		return a < b
+	})
+
+	return layers
+}
+
+// usedLayers 返回实体用到的图层，"0" 层始终在最前
+func (dw *docWriter) usedLayers() []string {
+	var (
+		seen   = map[string]bool{"0": true}
+		layers []string
+	)
+
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			layers = append(layers, name)
+		}
+	}
+
+	dw.eachEntity(func(e entities.Entity) {
+		add(e.Layer())
+		switch e := e.(type) {
+		case *entities.Insert:
+			for _, attr := range e.Attributes {
+				add(attr.Layer())
+			}
+		case *entities.Polyline:
+			for _, vertex := range e.Vertices {
+				add(vertex.Layer())
+			}
+		}
+	})
+	sort.Strings(layers)
+
+	return append([]string{"0"}, layers...)
+}
+
+// dimStyles 返回需要写入 DIMSTYLE 表的标注样式，保证存在 STANDARD
+func (dw *docWriter) dimStyles() []*DimStyle {
+	var styles []*DimStyle
+	for _, style := range dw.doc.DimStyles {
+		styles = append(styles, style)
+	}
+	if _, ok := dw.doc.DimStyles["STANDARD"]; !ok {
+		styles = append(styles, &DimStyle{Name: "Standard", Scale: 1.0})
+	}
+	sort.Slice(styles, func(i, j int) bool {
+		return strings.ToUpper(styles[i].Name) < strings.ToUpper(styles[j].Name)
+	})
+
+	return styles
+}
+
+// blockNames 返回排序后的块名，不含模型空间与图纸空间
+func (dw *docWriter) blockNames() []string {
+	var names []string
+	for name := range dw.doc.Blocks {
+		if name = strings.ToUpper(name); name != "*MODEL_SPACE" && name != "*PAPER_SPACE" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// extents 计算模型空间实体的范围，用于设置初始视口
+func (dw *docWriter) extents() core.BBox {
+	if dw.doc.Header.Has("$EXTMIN") && dw.doc.Header.Has("$EXTMAX") {
+		return dw.doc.Header.Extents()
+	}
+
+	box := core.BBox{
+		Min: core.Point{X: math.MaxFloat64, Y: math.MaxFloat64},
+		Max: core.Point{X: -math.MaxFloat64, Y: -math.MaxFloat64},
+	}
+	for _, e := range dw.doc.Entities {
+		// 跳过构造线等无限的包围盒与没有几何图形的实体
+		if entities.IsEmpty(e) {
+			continue
+		}
+		b := e.BBox()
+		if !b.Bounded() {
+			continue
+		}
+		box.Min.X = math.Min(box.Min.X, b.Min.X)
+		box.Min.Y = math.Min(box.Min.Y, b.Min.Y)
+		box.Max.X = math.Max(box.Max.X, b.Max.X)
+		box.Max.Y = math.Max(box.Max.Y, b.Max.Y)
+	}
+	if box.Min.X > box.Max.X {
+		return core.BBox{Max: core.Point{X: 100, Y: 100}}
+	}
+
+	return box
+}
+
+// splitGroups 拆分出 102 组，其余组码保持原有顺序
+func splitGroups(tags []core.Tag) (groups, rest []core.Tag) {
+	var inGroup bool
+	for _, tag := range tags {
+		if inGroup || tag.Code == 102 {
+			if tag.Code == 102 {
+				inGroup = strings.HasPrefix(tag.Value, "{")
+			}
+			groups = append(groups, tag)
+			continue
+		}
+		rest = append(rest, tag)
+	}
+	return
+}
+
+// isLayoutBlock 判断是否为模型空间或图纸空间块
+func isLayoutBlock(name string) bool {
+	name = strings.ToUpper(name)
+	return name == "*MODEL_SPACE" || strings.HasPrefix(name, "*PAPER_SPACE")
+}
@@ -0,0 +1,181 @@
+package dxf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zooyer/dxf/core"
+	"github.com/zooyer/dxf/entities"
+)
+
+func TestDocument_WriteTo(t *testing.T) {
+	doc := New()
+	doc.Blocks["WIN"] = &Block{
+		Name:     "WIN",
+		Entities: []entities.Entity{entities.NewRectangle("PJ", core.BBox{Max: core.Point{X: 1500, Y: 1800}})},
+	}
+
+	insert := entities.NewInsert("0", "WIN", core.Point{X: 100, Y: 200})
+	insert.Attributes = append(insert.Attributes, &entities.Attrib{
+		BaseEntity: entities.BaseEntity{TypeName: "ATTRIB"},
+		Tag:        "楼号",
+		Text:       "  10# ", // 首尾空格原样保留
+	})
+	doc.Entities = append(doc.Entities,
+		entities.NewLine("BZ", core.Point{}, core.Point{X: 10, Y: 10}),
+		insert,
+	)
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Header.Version() != "AC1015" || loaded.Header.String("$HANDSEED") == "" {
+		t.Errorf("HEADER 不正确: %v", loaded.Header.Vars)
+	}
+	if len(loaded.Entities) != 2 {
+		t.Fatalf("期望 2 个实体, 得到 %d", len(loaded.Entities))
+	}
+
+	line, ok := loaded.Entities[0].(*entities.Line)
+	if !ok || line.Layer() != "BZ" || line.End.X != 10 {
+		t.Errorf("LINE 不正确: %+v", loaded.Entities[0])
+	}
+
+	ins, ok := loaded.Entities[1].(*entities.Insert)
+	if !ok || ins.BlockName != "WIN" || ins.InsertionPoint.Y != 200 {
+		t.Fatalf("INSERT 不正确: %+v", loaded.Entities[1])
+	}
+	if len(ins.Attributes) != 1 || ins.Attributes[0].Tag != "楼号" || ins.Attributes[0].Text != "  10# " {
+		t.Errorf("ATTRIB 不正确: %+v", ins.Attributes)
+	}
+	if ins.Handle == "" || ins.Handle == line.Handle || ins.SeqEnd == "" || ins.Owner == "" {
+		t.Errorf("句柄不正确: line=%q insert=%q", line.Handle, ins.Handle)
+	}
+
+	// 新分配的句柄与所属对象只用于写出，不修改文档
+	if insert.Handle != "" || insert.Owner != "" || insert.SeqEnd != "" || insert.Attributes[0].Owner != "" ||
+		doc.Blocks["WIN"].Handle != "" || doc.Blocks["WIN"].Entities[0].Base().Owner != "" {
+		t.Errorf("写出修改了文档: %+v", insert.BaseEntity)
+	}
+
+	block, ok := loaded.Blocks["WIN"]
+	if !ok || len(block.Entities) != 1 {
+		t.Fatalf("块 WIN 不正确: %+v", block)
+	}
+	if box := block.Entities[0].BBox(); box.Max.X != 1500 || box.Max.Y != 1800 {
+		t.Errorf("块内多段线不正确: %+v", box)
+	}
+}
+
+func TestDocument_RoundTrip(t *testing.T) {
+	data := "0\nSECTION\n2\nHEADER\n9\n$ACADVER\n1\nAC1018\n9\n$HANDSEED\n5\n100\n9\n$INSUNITS\n70\n4\n0\nENDSEC\n" +
+		"0\nSECTION\n2\nCLASSES\n0\nCLASS\n1\nWIPEOUTVARIABLES\n2\nAcDbWipeoutVariables\n0\nENDSEC\n" +
+		"0\nSECTION\n2\nTABLES\n" +
+		"0\nTABLE\n2\nLAYER\n5\n2\n330\n0\n100\nAcDbSymbolTable\n70\n1\n" +
+		"0\nLAYER\n5\n10\n330\n2\n100\nAcDbSymbolTableRecord\n100\nAcDbLayerTableRecord\n2\nPJ\n70\n0\n62\n3\n6\nContinuous\n" +
+		"0\nENDTAB\n0\nENDSEC\n" +
+		"0\nSECTION\n2\nBLOCKS\n" +
+		"0\nBLOCK\n5\n20\n8\n0\n2\nWin\n70\n0\n10\n0\n20\n0\n30\n0\n" +
+		"0\nLINE\n5\n21\n8\nPJ\n10\n0\n20\n0\n11\n1\n21\n0\n" +
+		"0\nLINE\n5\n22\n8\nPJ\n10\n0\n20\n0\n11\n0\n21\n1\n" +
+		"0\nENDBLK\n5\n23\n8\n0\n0\nENDSEC\n" +
+		"0\nSECTION\n2\nENTITIES\n" +
+		"0\nLINE\n5\n30\n102\n{ACAD_REACTORS\n330\n99\n102\n}\n330\n1F\n100\nAcDbEntity\n8\nPJ\n62\n1\n100\nAcDbLine\n39\n2.5\n10\n0\n20\n0\n30\n0\n11\n5\n21\n5\n31\n0\n1001\nMYAPP\n1000\nnote\n" +
+		"0\nWIPEOUT\n5\n31\n8\nPJ\n10\n1\n20\n2\n11\n3\n21\n4\n" +
+		"0\nENDSEC\n" +
+		"0\nSECTION\n2\nOBJECTS\n0\nDICTIONARY\n5\nC\n330\n0\n100\nAcDbDictionary\n0\nENDSEC\n0\nEOF\n"
+
+	doc, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Entities) != 2 {
+		t.Fatalf("期望 2 个实体, 得到 %d", len(doc.Entities))
+	}
+	raw, ok := doc.Entities[1].(*entities.RawEntity)
+	if !ok || raw.Type() != "WIPEOUT" || raw.Handle != "31" || len(raw.Tags) != 6 {
+		t.Fatalf("未知实体未保留: %+v", doc.Entities[1])
+	}
+	if box := raw.BBox(); box.Min.X != 1 || box.Max.Y != 4 {
+		t.Errorf("RawEntity 包围盒不正确: %+v", box)
+	}
+	line := doc.Entities[0].(*entities.Line)
+	if len(line.Extra) != 7 || line.Owner != "1F" {
+		t.Errorf("未识别组码未保留: %+v", line.Extra)
+	}
+	if block := doc.Blocks["WIN"]; block == nil || len(block.Entities) != 2 || block.Name != "Win" {
+		t.Errorf("块内实体丢失: %+v", block)
+	}
+
+	var first, second bytes.Buffer
+	if _, err = doc.WriteTo(&first); err != nil {
+		t.Fatal(err)
+	}
+	again, err := Load(bytes.NewReader(first.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = again.WriteTo(&second); err != nil {
+		t.Fatal(err)
+	}
+	if first.String() != second.String() {
+		t.Errorf("两次写出不一致:\n%s\n----\n%s", first.String(), second.String())
+	}
+
+	out := first.String()
+	for _, want := range []string{"AC1018", "WIPEOUTVARIABLES", "{ACAD_REACTORS", "MYAPP", " 62\n1\n", " 39\n2.5\n", "WIPEOUT\n  5\n31\n", "PJ\n 70\n0\n 62\n3\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("写出结果缺少 %q", want)
+		}
+	}
+	if again.Header.InsUnits() != 4 {
+		t.Errorf("HEADER 未保留: %v", again.Header.Vars)
+	}
+	if h := again.Entities[0].Base().Handle; h != "30" {
+		t.Errorf("句柄未保留: 得到 %q", h)
+	}
+}
+
+func TestDocument_WriteToReserve(t *testing.T) {
+	// 没有 $HANDSEED，原样写出的表记录与 OBJECTS 段中的句柄不能被重新分配，分别以较大的句柄验证两者
+	for _, handles := range [][2]string{{"9", "8"}, {"8", "9"}} {
+		data := "0\nSECTION\n2\nTABLES\n" +
+			"0\nTABLE\n2\nSTYLE\n5\n3\n70\n1\n" +
+			"0\nSTYLE\n5\n" + handles[0] + "\n100\nAcDbSymbolTableRecord\n100\nAcDbTextStyleTableRecord\n2\nStandard\n70\n0\n" +
+			"0\nENDTAB\n0\nENDSEC\n" +
+			"0\nSECTION\n2\nOBJECTS\n0\nDICTIONARY\n5\n" + handles[1] + "\n330\n0\n100\nAcDbDictionary\n0\nENDSEC\n0\nEOF\n"
+
+		doc, err := Load(strings.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc.Entities = append(doc.Entities, entities.NewLine("0", core.Point{}, core.Point{X: 1}))
+
+		var buf bytes.Buffer
+		if _, err = doc.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		seen := make(map[string]bool)
+		lines := strings.Split(buf.String(), "\n")
+		for i := 0; i+1 < len(lines); i += 2 {
+			if code := strings.TrimSpace(lines[i]); code != "5" && code != "105" {
+				continue
+			}
+			if handle := lines[i+1]; seen[handle] {
+				t.Errorf("句柄 %s 重复", handle)
+			} else {
+				seen[handle] = true
+			}
+		}
+	}
+}